@@ -3,8 +3,10 @@ package checkout
 import (
 	"fmt"
 
+	"github.com/jacobdrury/grove/cmd/completion"
 	"github.com/jacobdrury/grove/internal/config"
 	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/jacobdrury/grove/internal/shell"
 	"github.com/spf13/cobra"
 )
 
@@ -15,16 +17,23 @@ var Command = &cobra.Command{
 	Args:              cobra.ExactArgs(1),
 	RunE:              run,
 	PersistentPreRunE: persistentPreRun,
+	ValidArgsFunction: completion.Branches,
 }
 
 var (
 	pipe    bool
 	noHooks bool
+	from    string
+	title   string
+	isNew   bool
 )
 
 func init() {
 	Command.Flags().BoolVarP(&pipe, "pipe", "p", false, "pipe worktree path to stdout")
 	Command.Flags().BoolVarP(&noHooks, "no-hooks", "n", false, "do not run hooks")
+	Command.Flags().StringVar(&from, "from", "", "commit, tag or branch to create a new branch from (defaults to the base branch)")
+	Command.Flags().StringVar(&title, "title", "", "title of the ticket a new branch is named after (defaults to the title provider's)")
+	Command.Flags().BoolVar(&isNew, "new", false, "create a new branch with the name as it is, without resolving it against existing branches")
 }
 
 func run(cmd *cobra.Command, args []string) error {
@@ -44,6 +53,9 @@ func run(cmd *cobra.Command, args []string) error {
 
 	wt, err := g.Checkout(ctx, grove.CheckoutArgs{
 		Branch: args[0],
+		From:   from,
+		Title:  title,
+		New:    isNew,
 	})
 	if err != nil {
 		return err
@@ -56,7 +68,7 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return nil
+	return shell.ChangeDirectory(wt.Path)
 }
 
 func persistentPreRun(cmd *cobra.Command, args []string) error {
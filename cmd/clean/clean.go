@@ -0,0 +1,145 @@
+package clean
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove the worktrees and branches that were merged or deleted on the remote",
+	Long: `Remove the worktrees of branches that were merged, squash-merged or rebased
+into their base branch, or whose upstream branch was deleted on the remote,
+along with their local branches.
+
+The worktrees that would be removed are listed first and each removal is
+confirmed, unless --yes is passed. Worktrees with uncommitted changes are
+never removed.`,
+	Args:              cobra.NoArgs,
+	RunE:              run,
+	PersistentPreRunE: persistentPreRun,
+}
+
+var (
+	yes     bool
+	dryRun  bool
+	noFetch bool
+	noHooks bool
+)
+
+func init() {
+	Command.Flags().BoolVarP(&yes, "yes", "y", false, "remove the worktrees without asking for confirmation")
+	Command.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "do not remove, show only")
+	Command.Flags().BoolVar(&noFetch, "no-fetch", false, "do not fetch and prune remote-tracking branches first")
+	Command.Flags().BoolVar(&noHooks, "no-hooks", false, "do not run hooks")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if noHooks {
+		ctx = config.ContextWithNoHooks(ctx)
+	}
+
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	candidates, err := g.PlanClean(ctx, grove.CleanArgs{Fetch: !noFetch})
+	if err != nil {
+		return err
+	}
+
+	if len(candidates) == 0 {
+		util.LogInfo(ctx, "nothing to clean")
+		return nil
+	}
+
+	err = writePlan(cmd.OutOrStdout(), candidates)
+	if err != nil {
+		return err
+	}
+
+	removable := lo.Filter(candidates, func(c grove.CleanCandidate, _ int) bool {
+		return c.Keep == ""
+	})
+
+	if dryRun || len(removable) == 0 {
+		return nil
+	}
+
+	if !yes && !util.IsInteractive() {
+		return errors.New("refusing to remove worktrees without confirmation, pass --yes to remove them")
+	}
+
+	reader := bufio.NewReader(cmd.InOrStdin())
+	all := yes
+
+	var errs []error
+	for _, c := range removable {
+		if !all {
+			answer, err := util.Prompt(reader, fmt.Sprintf("Remove %s and its worktree? [y]es, [n]o, [a]ll, [q]uit: ", c.Branch))
+			if err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+
+			switch answer {
+			case "y", "yes":
+			case "a", "all":
+				all = true
+			case "q", "quit":
+				return errors.Join(errs...)
+			default:
+				continue
+			}
+		}
+
+		_, err := g.Remove(ctx, grove.RemoveArgs{
+			Branch:       c.Branch,
+			DeleteBranch: true,
+			Merged:       true,
+		})
+		if err != nil {
+			// Keep going, the other worktrees can still be removed
+			slog.ErrorContext(ctx, "error removing worktree", slog.String("branch", c.Branch), slog.String("error", err.Error()))
+			errs = append(errs, fmt.Errorf("error removing %s: %w", c.Branch, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func writePlan(w io.Writer, candidates []grove.CleanCandidate) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "BRANCH\tREASON\tACTION\tPATH")
+	for _, c := range candidates {
+		reason := string(c.Reason)
+		if c.Base != "" {
+			reason += " into " + c.Base
+		}
+
+		action := "remove"
+		if c.Keep != "" {
+			action = "keep (" + c.Keep + ")"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Branch, reason, action, c.Path)
+	}
+
+	return tw.Flush()
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
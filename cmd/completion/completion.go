@@ -0,0 +1,96 @@
+package completion
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate the autocompletion script for the specified shell",
+	Long: `Generate the autocompletion script for grove for the specified shell.
+
+The shell integration installed by 'grove shell-init' loads completions
+automatically. To load completions without the shell integration:
+
+  bash:       source <(grove completion bash)
+  zsh:        source <(grove completion zsh)
+  fish:       grove completion fish | source
+  powershell: grove completion powershell | Out-String | Invoke-Expression`,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell", "pwsh"},
+	RunE:      run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	root := cmd.Root()
+	out := cmd.OutOrStdout()
+
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletionV2(out, true)
+	case "zsh":
+		return root.GenZshCompletion(out)
+	case "fish":
+		return root.GenFishCompletion(out, true)
+	case "powershell", "pwsh":
+		return root.GenPowerShellCompletionWithDesc(out)
+	default:
+		return fmt.Errorf("unsupported shell %q", args[0])
+	}
+}
+
+// Branches completes the first argument with branches, worktrees and prefix
+// aliases.
+func Branches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete(cmd, args, func(ctx context.Context, g *grove.Grove) ([]string, error) {
+		return g.CompleteBranch(ctx, toComplete)
+	})
+}
+
+// WorkTrees completes the first argument with the branches of existing
+// worktrees.
+func WorkTrees(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete(cmd, args, func(ctx context.Context, g *grove.Grove) ([]string, error) {
+		return g.CompleteWorkTree(ctx, toComplete)
+	})
+}
+
+func complete(cmd *cobra.Command, args []string, f func(context.Context, *grove.Grove) ([]string, error)) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Pre-run hooks are not executed when completing
+	err := grove.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	g, err := grove.GetInstance()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions, err := f(cmd.Context(), g)
+	if err != nil {
+		slog.DebugContext(cmd.Context(), "error completing branches", slog.String("error", err.Error()))
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	directive := cobra.ShellCompDirectiveNoFileComp
+	for _, c := range completions {
+		// Don't add a space after a prefix alias so the branch can be typed
+		if strings.HasSuffix(c, g.Config.BranchResolver.BranchDelimiter) {
+			directive |= cobra.ShellCompDirectiveNoSpace
+			break
+		}
+	}
+
+	return completions, directive
+}
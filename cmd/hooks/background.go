@@ -0,0 +1,18 @@
+package hooks
+
+import (
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+// runBackgroundCommand is run by grove itself in a detached process to
+// execute a background hook.
+var runBackgroundCommand = &cobra.Command{
+	Use:    "run-background <record>",
+	Short:  "Run a recorded background hook",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return grove.RunBackgroundHook(cmd.Context(), args[0])
+	},
+}
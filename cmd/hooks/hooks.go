@@ -0,0 +1,25 @@
+package hooks
+
+import (
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "hooks",
+	Short: "Inspect and manage hooks",
+}
+
+func init() {
+	Command.AddCommand(
+		listCommand,
+		runCommand,
+		statusCommand,
+		logCommand,
+		runBackgroundCommand,
+	)
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
@@ -0,0 +1,92 @@
+package hooks
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var listCommand = &cobra.Command{
+	Use:               "list [branch]",
+	Aliases:           []string{"ls"},
+	Short:             "List the hooks that run for each event",
+	Long:              "List the hooks that run for each event on this operating system. When a branch name is specified, hooks whose branch condition doesn't match it are left out.",
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runList,
+	PersistentPreRunE: persistentPreRun,
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "EVENT\tHOOK\tCOMMAND\tOPTIONS")
+	for _, event := range config.HookEvents() {
+		for _, hook := range g.Config.Hooks.ForEvent(event) {
+			if !(config.HookCondition{OS: hook.When.OS}).Matches("", runtime.GOOS) {
+				continue
+			}
+
+			if len(args) > 0 && !hook.When.Matches(args[0], runtime.GOOS) {
+				continue
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+				event,
+				hook.DisplayName(),
+				strings.ReplaceAll(hook.Run, "\n", "; "),
+				options(hook),
+			)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// options summarizes the options that affect when and how the hook runs.
+func options(hook config.Hook) string {
+	var opts []string
+
+	if hook.OnlyOn != config.HookOnlyOnAny {
+		opts = append(opts, "only-on="+string(hook.OnlyOn))
+	}
+
+	if hook.When.Branch != "" {
+		opts = append(opts, "branch="+hook.When.Branch)
+	}
+
+	if hook.Parallel {
+		opts = append(opts, "parallel")
+	}
+
+	if hook.Background {
+		opts = append(opts, "background")
+	}
+
+	if len(hook.DependsOn) > 0 {
+		opts = append(opts, "depends-on="+strings.Join(hook.DependsOn, ","))
+	}
+
+	if hook.Timeout > 0 {
+		opts = append(opts, "timeout="+hook.Timeout.String())
+	}
+
+	if hook.ContinueOnError {
+		opts = append(opts, "continue-on-error")
+	}
+
+	if len(opts) == 0 {
+		return "-"
+	}
+
+	return strings.Join(opts, " ")
+}
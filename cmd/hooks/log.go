@@ -0,0 +1,56 @@
+package hooks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var logCommand = &cobra.Command{
+	Use:               "log <id>",
+	Short:             "Show the output of a hook run",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runLog,
+	PersistentPreRunE: persistentPreRun,
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	run, err := g.HookRun(args[0])
+	if err != nil {
+		return err
+	}
+
+	output, err := run.Output()
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+
+	fmt.Fprintf(w, "hook:     %s\n", run.Name)
+	fmt.Fprintf(w, "event:    %s\n", run.Event)
+	fmt.Fprintf(w, "branch:   %s\n", run.Branch)
+	fmt.Fprintf(w, "command:  %s\n", run.Command)
+	fmt.Fprintf(w, "dir:      %s\n", run.Dir)
+	fmt.Fprintf(w, "started:  %s\n", run.StartedAt.Format(time.DateTime))
+	fmt.Fprintf(w, "duration: %s\n", run.Duration().Round(time.Millisecond))
+	fmt.Fprintf(w, "status:   %s\n", run.Status())
+	if run.ExitCode != nil {
+		fmt.Fprintf(w, "exit:     %d\n", *run.ExitCode)
+	}
+	if run.Error != "" {
+		fmt.Fprintf(w, "error:    %s\n", run.Error)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprint(w, output)
+
+	return nil
+}
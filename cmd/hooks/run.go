@@ -0,0 +1,50 @@
+package hooks
+
+import (
+	"github.com/jacobdrury/grove/cmd/completion"
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var runCommand = &cobra.Command{
+	Use:               "run <event> [branch]",
+	Short:             "Run an event's hooks for a worktree",
+	Long:              "Run an event's hooks for the branch's worktree, or the worktree in the current directory when no branch is specified.",
+	Args:              cobra.RangeArgs(1, 2),
+	RunE:              runHooks,
+	PersistentPreRunE: persistentPreRun,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return eventNames(), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return completion.WorkTrees(cmd, args[1:], toComplete)
+	},
+}
+
+func runHooks(cmd *cobra.Command, args []string) error {
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	var branch string
+	if len(args) > 1 {
+		branch = args[1]
+	}
+
+	return g.RunHooks(cmd.Context(), grove.RunHooksArgs{
+		Event:  config.HookEvent(args[0]),
+		Branch: branch,
+	})
+}
+
+func eventNames() []string {
+	var names []string
+	for _, event := range config.HookEvents() {
+		names = append(names, string(event))
+	}
+
+	return names
+}
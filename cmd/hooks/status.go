@@ -0,0 +1,78 @@
+package hooks
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jacobdrury/grove/cmd/completion"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var statusCommand = &cobra.Command{
+	Use:               "status [branch]",
+	Short:             "Show the results of recent hook runs",
+	Long:              "Show the results of recent hook runs, of every worktree or only the branch's worktree. Use `grove hooks log <id>` to see a run's output.",
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runStatus,
+	PersistentPreRunE: persistentPreRun,
+	ValidArgsFunction: completion.WorkTrees,
+}
+
+var (
+	limit int
+)
+
+func init() {
+	statusCommand.Flags().IntVarP(&limit, "limit", "l", 20, "maximum number of runs to show, all when zero")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	var branch string
+	if len(args) > 0 {
+		branch = args[0]
+	}
+
+	runs, err := g.HookRuns(cmd.Context(), branch)
+	if err != nil {
+		return err
+	}
+
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tSTATUS\tEXIT\tEVENT\tBRANCH\tHOOK\tSTARTED\tDURATION")
+	for _, run := range runs {
+		exitCode := "-"
+		if run.ExitCode != nil {
+			exitCode = fmt.Sprint(*run.ExitCode)
+		}
+
+		name := run.Name
+		if run.Background {
+			name += " (background)"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			run.ID,
+			run.Status(),
+			exitCode,
+			run.Event,
+			run.Branch,
+			name,
+			run.StartedAt.Format(time.DateTime),
+			run.Duration().Round(time.Millisecond),
+		)
+	}
+
+	return tw.Flush()
+}
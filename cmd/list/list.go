@@ -0,0 +1,170 @@
+package list
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatTSV   = "tsv"
+)
+
+var Command = &cobra.Command{
+	Use:               "list",
+	Aliases:           []string{"ls"},
+	Short:             "List worktrees and their status",
+	Args:              cobra.NoArgs,
+	RunE:              run,
+	PersistentPreRunE: persistentPreRun,
+}
+
+var (
+	format string
+)
+
+func init() {
+	Command.Flags().StringVarP(&format, "format", "f", formatTable, "output format (table, json, tsv)")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var write func(io.Writer, []grove.WorkTreeStatus) error
+	switch format {
+	case formatTable:
+		write = writeTable
+	case formatJSON:
+		write = writeJSON
+	case formatTSV:
+		write = writeTSV
+	default:
+		return fmt.Errorf("invalid format %q, must be one of %s, %s, %s", format, formatTable, formatJSON, formatTSV)
+	}
+
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	statuses, err := g.List(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	return write(cmd.OutOrStdout(), statuses)
+}
+
+func writeTable(w io.Writer, statuses []grove.WorkTreeStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "BRANCH\tSTATE\tSYNC\tLAST COMMIT\tSUBJECT\tPATH")
+	for _, s := range statuses {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			branch(s),
+			state(s),
+			syncState(s),
+			s.LastCommitDate.Format(time.DateOnly),
+			s.LastCommitSubject,
+			s.Path,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, statuses []grove.WorkTreeStatus) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(statuses)
+}
+
+func writeTSV(w io.Writer, statuses []grove.WorkTreeStatus) error {
+	_, err := fmt.Fprintln(w, "branch\tpath\thead\tbare\tdetached\tdirty\tupstream\tahead\tbehind\tlast_commit_date\tlast_commit_subject\tlocked\tprunable\tmerged")
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%t\t%t\t%s\t%d\t%d\t%s\t%s\t%t\t%t\t%t\n",
+			s.Branch,
+			s.Path,
+			s.Head,
+			s.Bare,
+			s.Detached,
+			s.Dirty,
+			s.Upstream,
+			s.Ahead,
+			s.Behind,
+			s.LastCommitDate.Format(time.RFC3339),
+			strings.ReplaceAll(s.LastCommitSubject, "\t", " "),
+			s.Locked,
+			s.Prunable,
+			s.Merged,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// branch returns the branch name, or a description of the worktree if it
+// does not have a branch checked out.
+func branch(s grove.WorkTreeStatus) string {
+	switch {
+	case s.Bare:
+		return "(bare)"
+	case s.Detached:
+		return "(detached)"
+	default:
+		return s.Branch
+	}
+}
+
+// state summarizes the worktree state flags into a single column.
+func state(s grove.WorkTreeStatus) string {
+	flags := []string{"clean"}
+	if s.Dirty {
+		flags[0] = "dirty"
+	}
+
+	if s.Bare {
+		flags[0] = "bare"
+	}
+
+	if s.Locked {
+		flags = append(flags, "locked")
+	}
+
+	if s.Prunable {
+		flags = append(flags, "prunable")
+	}
+
+	if s.Merged {
+		flags = append(flags, "merged")
+	}
+
+	return strings.Join(flags, ",")
+}
+
+// syncState summarizes how the worktree compares to its upstream.
+func syncState(s grove.WorkTreeStatus) string {
+	if s.Upstream == "" {
+		return "-"
+	}
+
+	return fmt.Sprintf("+%d/-%d", s.Ahead, s.Behind)
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
@@ -0,0 +1,63 @@
+package prune
+
+import (
+	"fmt"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:               "prune",
+	Short:             "Prune worktree information for worktrees that no longer exist",
+	Args:              cobra.NoArgs,
+	RunE:              run,
+	PersistentPreRunE: persistentPreRun,
+}
+
+var (
+	dryRun  bool
+	verbose bool
+	expire  string
+	noHooks bool
+)
+
+func init() {
+	Command.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "do not remove, show only")
+	// Shadows the global --verbose, debug messages are logged with
+	// LOG_LEVEL=debug instead
+	Command.Flags().BoolVarP(&verbose, "verbose", "v", false, "report pruned worktrees")
+	Command.Flags().StringVar(&expire, "expire", "", "expire worktrees older than <time>")
+	Command.Flags().BoolVar(&noHooks, "no-hooks", false, "do not run hooks")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if noHooks {
+		ctx = config.ContextWithNoHooks(ctx)
+	}
+
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	res, err := g.Prune(ctx, grove.PruneArgs{
+		DryRun:  dryRun,
+		Verbose: verbose,
+		Expire:  expire,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), res.Stdout)
+	fmt.Fprint(cmd.ErrOrStderr(), res.Stderr)
+
+	return nil
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
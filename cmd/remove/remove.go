@@ -0,0 +1,54 @@
+package remove
+
+import (
+	"github.com/jacobdrury/grove/cmd/completion"
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:               "remove",
+	Aliases:           []string{"rm"},
+	Short:             "Remove a branch's worktree",
+	Args:              cobra.ExactArgs(1),
+	RunE:              run,
+	PersistentPreRunE: persistentPreRun,
+	ValidArgsFunction: completion.WorkTrees,
+}
+
+var (
+	force        bool
+	deleteBranch bool
+	noHooks      bool
+)
+
+func init() {
+	Command.Flags().BoolVarP(&force, "force", "f", false, "remove the worktree even if it has uncommitted or unpushed changes")
+	Command.Flags().BoolVarP(&deleteBranch, "delete-branch", "d", false, "delete the local branch after removing the worktree")
+	Command.Flags().BoolVarP(&noHooks, "no-hooks", "n", false, "do not run hooks")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if noHooks {
+		ctx = config.ContextWithNoHooks(ctx)
+	}
+
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	_, err = g.Remove(ctx, grove.RemoveArgs{
+		Branch:       args[0],
+		Force:        force,
+		DeleteBranch: deleteBranch,
+	})
+
+	return err
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
@@ -2,26 +2,41 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"strings"
 
 	"github.com/jacobdrury/grove/cmd/checkout"
+	"github.com/jacobdrury/grove/cmd/clean"
+	"github.com/jacobdrury/grove/cmd/completion"
+	"github.com/jacobdrury/grove/cmd/hooks"
 	"github.com/jacobdrury/grove/cmd/initialize"
+	"github.com/jacobdrury/grove/cmd/list"
+	"github.com/jacobdrury/grove/cmd/prune"
+	"github.com/jacobdrury/grove/cmd/remove"
+	"github.com/jacobdrury/grove/cmd/seed"
+	"github.com/jacobdrury/grove/cmd/shellinit"
+	"github.com/jacobdrury/grove/cmd/status"
+	"github.com/jacobdrury/grove/cmd/switchworktree"
 	"github.com/jacobdrury/grove/cmd/version"
 	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
 	Use:           "grove",
 	Short:         "Grove is a wrapper around the `git worktree` command.",
 	SilenceErrors: true, // Errors are output to stderr so we don't need to print them
+	// Flags are forwarded untouched to `git worktree`, e.g. `grove lock --reason "wip stuff" <path>`
+	DisableFlagParsing: true,
 	Args: func(cmd *cobra.Command, args []string) error {
 		// If there are args, check if the first is a valid subcommand
-		if len(args) < 1 {
+		if len(args) < 1 || isHelp(args[0]) {
 			return nil
 		}
 
@@ -32,19 +47,32 @@ var rootCmd = &cobra.Command{
 			return nil
 		}
 
-		slog.DebugContext(cmd.Context(), "no subcommand found, passing through args to git worktree command", slog.String("args", strings.Join(args, " ")))
+		// Flag parsing is disabled, so parse the global flags preceding the
+		// git worktree command ourselves
+		args, err := parseLeadingFlags(cmd.PersistentFlags(), args)
+		if err != nil {
+			return err
+		}
 
-		output, err := git.ExecuteWorkTree(cmd.Context(), strings.Join(args, " "))
+		err = configureLogging()
 		if err != nil {
 			return err
 		}
 
-		fmt.Print(output)
+		slog.DebugContext(cmd.Context(), "no subcommand found, passing through args to git worktree command", slog.Any("args", args))
+
+		res, err := git.ExecuteWorkTree(cmd.Context(), args...)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(cmd.OutOrStdout(), res.Stdout)
+		fmt.Fprint(cmd.ErrOrStderr(), res.Stderr)
 
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 1 {
+		if len(args) < 1 || isHelp(args[0]) {
 			return cmd.Help()
 		}
 
@@ -52,6 +80,88 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+var (
+	quiet     bool
+	verbose   bool
+	logFormat string
+)
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "--help"
+}
+
+// parseLeadingFlags sets the flags at the start of args and returns the
+// remaining args, starting at the first arg that isn't one of the flags.
+func parseLeadingFlags(flags *pflag.FlagSet, args []string) ([]string, error) {
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[0], "-"), "=")
+
+		var flag *pflag.Flag
+		switch {
+		case strings.HasPrefix(args[0], "--"):
+			flag = flags.Lookup(name)
+		case len(name) == 1:
+			flag = flags.ShorthandLookup(name)
+		}
+
+		if flag == nil {
+			break
+		}
+
+		args = args[1:]
+
+		if !hasValue {
+			switch {
+			case flag.NoOptDefVal != "":
+				value = flag.NoOptDefVal
+			case len(args) > 0:
+				value, args = args[0], args[1:]
+			default:
+				return nil, fmt.Errorf("flag needs an argument: --%s", flag.Name)
+			}
+		}
+
+		err := flags.Set(flag.Name, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %q for --%s: %w", value, flag.Name, err)
+		}
+	}
+
+	return args, nil
+}
+
+// configureLogging sets the default logger from the global flags. The level
+// defaults to $LOG_LEVEL, or info when it isn't set.
+func configureLogging() error {
+	if quiet && verbose {
+		return errors.New("--quiet and --verbose are mutually exclusive")
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case verbose:
+		level = slog.LevelDebug
+	case quiet:
+		level = slog.LevelWarn
+	default:
+		if env := os.Getenv("LOG_LEVEL"); env != "" {
+			err := level.UnmarshalText([]byte(env))
+			if err != nil {
+				return fmt.Errorf("invalid log level %s: %w", env, err)
+			}
+		}
+	}
+
+	logger, err := util.NewLogger(level, util.LogFormat(logFormat))
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(logger)
+
+	return nil
+}
+
 func Execute(ctx context.Context) {
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		slog.Error(err.Error())
@@ -60,7 +170,21 @@ func Execute(ctx context.Context) {
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only log warnings and errors")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "log debug messages")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", string(util.LogFormatText), "format of log messages written to stderr (text or json)")
+
+	// Log with the defaults until the flags are parsed
+	err := configureLogging()
+	if err != nil {
+		panic(err)
+	}
+
 	cobra.OnInitialize(
+		func() {
+			err := configureLogging()
+			cobra.CheckErr(err)
+		},
 		func() {
 			err := git.ValidateGitInstallation()
 			cobra.CheckErr(err)
@@ -69,9 +193,20 @@ func init() {
 
 	rootCmd.AddCommand(
 		checkout.Command,
+		clean.Command,
+		completion.Command,
+		hooks.Command,
 		initialize.Command,
+		list.Command,
+		prune.Command,
+		remove.Command,
+		seed.Command,
+		shellinit.Command,
+		status.Command,
+		switchworktree.Command,
 		version.Command,
 	)
 
 	rootCmd.SilenceUsage = true
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
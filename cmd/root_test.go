@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestParseLeadingFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		rest      []string
+		quiet     bool
+		verbose   bool
+		logFormat string
+		wantErr   bool
+	}{
+		{name: "none", args: []string{"prune", "-v"}, rest: []string{"prune", "-v"}},
+		{name: "bool", args: []string{"--verbose", "prune"}, rest: []string{"prune"}, verbose: true},
+		{name: "shorthand", args: []string{"-q", "lock", "x"}, rest: []string{"lock", "x"}, quiet: true},
+		{name: "bool value", args: []string{"--quiet=false", "prune"}, rest: []string{"prune"}},
+		{name: "separate value", args: []string{"--log-format", "json", "prune"}, rest: []string{"prune"}, logFormat: "json"},
+		{name: "inline value", args: []string{"--log-format=json", "--verbose", "prune"}, rest: []string{"prune"}, verbose: true, logFormat: "json"},
+		{name: "only flags", args: []string{"--verbose"}, rest: []string{}, verbose: true},
+		// Flags of the git worktree command are passed through
+		{name: "unknown flag", args: []string{"--porcelain", "--verbose"}, rest: []string{"--porcelain", "--verbose"}},
+		{name: "flags after command", args: []string{"list", "--verbose"}, rest: []string{"list", "--verbose"}},
+		{name: "missing value", args: []string{"--log-format"}, wantErr: true},
+		{name: "invalid value", args: []string{"--quiet=maybe", "prune"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var quiet, verbose bool
+			var logFormat string
+
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.BoolVarP(&quiet, "quiet", "q", false, "")
+			flags.BoolVar(&verbose, "verbose", false, "")
+			flags.StringVar(&logFormat, "log-format", "", "")
+
+			rest, err := parseLeadingFlags(flags, tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseLeadingFlags(%v) error = %v, wantErr %v", tc.args, err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			if !slices.Equal(rest, tc.rest) {
+				t.Errorf("parseLeadingFlags(%v) = %v, want %v", tc.args, rest, tc.rest)
+			}
+
+			if quiet != tc.quiet || verbose != tc.verbose || logFormat != tc.logFormat {
+				t.Errorf("quiet, verbose, log-format = %v, %v, %q, want %v, %v, %q", quiet, verbose, logFormat, tc.quiet, tc.verbose, tc.logFormat)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package seed
+
+import (
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "seed",
+	Short: "Inspect the files seeded into worktrees",
+}
+
+func init() {
+	Command.AddCommand(
+		statusCommand,
+	)
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
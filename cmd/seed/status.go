@@ -0,0 +1,70 @@
+package seed
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/jacobdrury/grove/cmd/completion"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/spf13/cobra"
+)
+
+var statusCommand = &cobra.Command{
+	Use:               "status [branch]",
+	Short:             "Show how seeded files have drifted from the seed",
+	Long:              "Show how the files seeded into the branch's worktree, or the worktree in the current directory when no branch is specified, have drifted from the seed.",
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runStatus,
+	PersistentPreRunE: persistentPreRun,
+	ValidArgsFunction: completion.WorkTrees,
+}
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+var (
+	format string
+)
+
+func init() {
+	statusCommand.Flags().StringVarP(&format, "format", "f", formatTable, "output format (table, json)")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	var branch string
+	if len(args) > 0 {
+		branch = args[0]
+	}
+
+	_, statuses, err := g.SeedStatus(cmd.Context(), branch)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "PATH\tMODE\tSTATUS")
+		for _, status := range statuses {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", status.Path, status.Mode, status.Status)
+		}
+
+		return tw.Flush()
+	case formatJSON:
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(statuses)
+	default:
+		return fmt.Errorf("invalid format %q, must be one of %s, %s", format, formatTable, formatJSON)
+	}
+}
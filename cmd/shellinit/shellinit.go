@@ -0,0 +1,26 @@
+package shellinit
+
+import (
+	"fmt"
+
+	"github.com/jacobdrury/grove/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:       "shell-init <bash|zsh|fish|pwsh>",
+	Short:     "Print the shell integration script that changes directory after checkout",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: shell.Shells(),
+	RunE:      run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	script, err := shell.InitScript(args[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), script)
+	return err
+}
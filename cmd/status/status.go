@@ -0,0 +1,226 @@
+package status
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+const (
+	sortBranch   = "branch"
+	sortDirty    = "dirty"
+	sortStashes  = "stashes"
+	sortUnpushed = "unpushed"
+	sortTouched  = "touched"
+	sortSize     = "size"
+)
+
+var Command = &cobra.Command{
+	Use:   "status",
+	Short: "Show a summary of every worktree to find stale ones",
+	Long: `Show a summary of every worktree: the number of files with uncommitted
+changes, stashes and unpushed commits, whether the upstream branch was
+deleted on the remote, when the worktree was last touched and its size.`,
+	Args:              cobra.NoArgs,
+	RunE:              run,
+	PersistentPreRunE: persistentPreRun,
+}
+
+var (
+	format   string
+	sortBy   string
+	reverse  bool
+	dirty    bool
+	stashed  bool
+	unpushed bool
+	gone     bool
+	stale    string
+)
+
+func init() {
+	Command.Flags().StringVarP(&format, "format", "f", formatTable, "output format (table, json)")
+	Command.Flags().StringVarP(&sortBy, "sort", "s", sortBranch, "sort by branch, dirty, stashes, unpushed, touched (least recent first) or size")
+	Command.Flags().BoolVarP(&reverse, "reverse", "r", false, "reverse the sort order")
+	Command.Flags().BoolVar(&dirty, "dirty", false, "only show worktrees with uncommitted changes")
+	Command.Flags().BoolVar(&stashed, "stashed", false, "only show worktrees with stashes")
+	Command.Flags().BoolVar(&unpushed, "unpushed", false, "only show worktrees with unpushed commits")
+	Command.Flags().BoolVar(&gone, "gone", false, "only show worktrees whose upstream was deleted on the remote")
+	Command.Flags().StringVar(&stale, "stale", "", "only show worktrees not touched for a duration, e.g. 2w, 10d or 36h")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var write func(io.Writer, []grove.WorkTreeSummary) error
+	switch format {
+	case formatTable:
+		write = writeTable
+	case formatJSON:
+		write = writeJSON
+	default:
+		return fmt.Errorf("invalid format %q, must be one of %s, %s", format, formatTable, formatJSON)
+	}
+
+	compare, err := comparer(sortBy)
+	if err != nil {
+		return err
+	}
+
+	var staleAfter time.Duration
+	if stale != "" {
+		staleAfter, err = parseAge(stale)
+		if err != nil {
+			return fmt.Errorf("invalid --stale duration %q: %w", stale, err)
+		}
+	}
+
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	summaries, err := g.Status(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	summaries = slices.DeleteFunc(summaries, func(s grove.WorkTreeSummary) bool {
+		return (dirty && s.DirtyFiles == 0) ||
+			(stashed && s.Stashes == 0) ||
+			(unpushed && s.Unpushed == 0) ||
+			(gone && !s.UpstreamGone) ||
+			(staleAfter > 0 && time.Since(s.LastTouched) < staleAfter)
+	})
+
+	slices.SortStableFunc(summaries, func(a, b grove.WorkTreeSummary) int {
+		if reverse {
+			return compare(b, a)
+		}
+
+		return compare(a, b)
+	})
+
+	return write(cmd.OutOrStdout(), summaries)
+}
+
+// comparer returns the function that orders summaries by the field. Counts
+// and sizes are ordered largest first.
+func comparer(field string) (func(a, b grove.WorkTreeSummary) int, error) {
+	switch field {
+	case sortBranch:
+		return func(a, b grove.WorkTreeSummary) int { return cmp.Compare(a.Branch, b.Branch) }, nil
+	case sortDirty:
+		return func(a, b grove.WorkTreeSummary) int { return cmp.Compare(b.DirtyFiles, a.DirtyFiles) }, nil
+	case sortStashes:
+		return func(a, b grove.WorkTreeSummary) int { return cmp.Compare(b.Stashes, a.Stashes) }, nil
+	case sortUnpushed:
+		return func(a, b grove.WorkTreeSummary) int { return cmp.Compare(b.Unpushed, a.Unpushed) }, nil
+	case sortTouched:
+		return func(a, b grove.WorkTreeSummary) int { return a.LastTouched.Compare(b.LastTouched) }, nil
+	case sortSize:
+		return func(a, b grove.WorkTreeSummary) int { return cmp.Compare(b.DiskUsage, a.DiskUsage) }, nil
+	default:
+		return nil, fmt.Errorf("invalid sort %q, must be one of %s, %s, %s, %s, %s, %s", field, sortBranch, sortDirty, sortStashes, sortUnpushed, sortTouched, sortSize)
+	}
+}
+
+// parseAge parses a duration that may also be specified in days or weeks,
+// e.g. 10d or 2w.
+func parseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil {
+				return 0, err
+			}
+
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	return time.ParseDuration(s)
+}
+
+func writeTable(w io.Writer, summaries []grove.WorkTreeSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "BRANCH\tDIRTY\tSTASHES\tUNPUSHED\tUPSTREAM\tTOUCHED\tSIZE\tPATH")
+	for _, s := range summaries {
+		if s.Prunable {
+			fmt.Fprintf(tw, "%s\t-\t%d\t-\t%s\t-\t-\t%s (prunable)\n", branch(s), s.Stashes, upstream(s), s.Path)
+			continue
+		}
+
+		if s.Error != "" {
+			// git's errors continue with hints on the following lines
+			msg, _, _ := strings.Cut(s.Error, "\n")
+			fmt.Fprintf(tw, "%s\t-\t%d\t-\t%s\t-\t-\t%s (error: %s)\n", branch(s), s.Stashes, upstream(s), s.Path, msg)
+			continue
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\n",
+			branch(s),
+			s.DirtyFiles,
+			s.Stashes,
+			s.Unpushed,
+			upstream(s),
+			util.FormatAgo(s.LastTouched),
+			util.FormatBytes(s.DiskUsage),
+			s.Path,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, summaries []grove.WorkTreeSummary) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(summaries)
+}
+
+// branch returns the branch name, or a description of the worktree if it
+// does not have a branch checked out.
+func branch(s grove.WorkTreeSummary) string {
+	if s.Detached {
+		return "(detached)"
+	}
+
+	return s.Branch
+}
+
+func upstream(s grove.WorkTreeSummary) string {
+	switch {
+	case s.Upstream == "" && s.Detached:
+		return "-"
+	case s.Upstream == "":
+		return "(not pushed)"
+	case s.UpstreamGone:
+		return s.Upstream + " (gone)"
+	default:
+		return s.Upstream
+	}
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
@@ -0,0 +1,163 @@
+package switchworktree
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/grove"
+	"github.com/jacobdrury/grove/internal/picker"
+	"github.com/jacobdrury/grove/internal/shell"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+const (
+	previewCommits = 10
+	previewChanges = 10
+)
+
+var Command = &cobra.Command{
+	Use:     "switch",
+	Aliases: []string{"sw"},
+	Short:   "Pick a worktree or branch to switch to with a fuzzy finder",
+	Long: `Pick a worktree or branch to switch to with a fuzzy finder, ranked by when
+it was last checked out. The latest commits and uncommitted changes of the
+selected worktree are previewed.
+
+The picked worktree is checked out, creating it for branches without one,
+and its path is printed, so the shell integration changes into it.`,
+	Args:              cobra.NoArgs,
+	RunE:              run,
+	PersistentPreRunE: persistentPreRun,
+}
+
+var (
+	noHooks bool
+)
+
+func init() {
+	Command.Flags().BoolVarP(&noHooks, "no-hooks", "n", false, "do not run hooks")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if noHooks {
+		ctx = config.ContextWithNoHooks(ctx)
+	}
+
+	if !util.IsInteractive() {
+		return errors.New("switch needs a terminal, use `grove checkout <branch>` instead")
+	}
+
+	g, err := grove.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	candidates, err := g.SwitchCandidates(ctx)
+	if err != nil {
+		return err
+	}
+
+	p := picker.Picker{
+		Prompt: "switch> ",
+		Items: lo.Map(candidates, func(c grove.SwitchCandidate, _ int) picker.Item {
+			return picker.Item{Text: c.Branch, Detail: detail(c)}
+		}),
+		Preview: func(i int) string {
+			return preview(git.ContextWithDir(ctx, g.RepositoryPath), candidates[i])
+		},
+	}
+
+	i, err := p.Run()
+	if errors.Is(err, picker.ErrCanceled) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	wt, err := g.Checkout(ctx, grove.CheckoutArgs{Branch: candidates[i].Branch})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), wt.Path)
+	if err != nil {
+		return err
+	}
+
+	return shell.ChangeDirectory(wt.Path)
+}
+
+// detail describes when the candidate was last used.
+func detail(c grove.SwitchCandidate) string {
+	switch {
+	case c.Path == "":
+		return "no worktree"
+	case c.LastUsed.IsZero():
+		return "worktree"
+	default:
+		return "used " + util.FormatAgo(c.LastUsed)
+	}
+}
+
+// preview shows the candidate's latest commits and, for worktrees, their
+// uncommitted changes. Errors are shown in the preview rather than aborting
+// the picker.
+func preview(ctx context.Context, c grove.SwitchCandidate) string {
+	var b strings.Builder
+
+	ref := c.Branch
+	if c.Path == "" {
+		fmt.Fprintln(&b, "No worktree, checking it out creates one")
+
+		// Branches without a worktree may only exist on the remote
+		if !git.RefExists(ctx, "refs/heads/"+ref) {
+			ref = "origin/" + ref
+		}
+	} else {
+		fmt.Fprintln(&b, c.Path)
+
+		changed, err := git.ListChangedFiles(git.ContextWithDir(ctx, c.Path))
+		switch {
+		case err != nil:
+			fmt.Fprintf(&b, "Error listing changes: %v\n", err)
+		case len(changed) == 0:
+			fmt.Fprintln(&b, "No uncommitted changes")
+		default:
+			fmt.Fprintln(&b, "Uncommitted changes:")
+			for _, file := range lo.Slice(changed, 0, previewChanges) {
+				fmt.Fprintf(&b, "  %s\n", file)
+			}
+
+			if len(changed) > previewChanges {
+				fmt.Fprintf(&b, "  and %d more\n", len(changed)-previewChanges)
+			}
+		}
+	}
+
+	fmt.Fprintln(&b)
+
+	commits, err := git.ListCommits(ctx, ref, previewCommits)
+	if err != nil {
+		fmt.Fprintf(&b, "Error listing commits: %v\n", err)
+		return b.String()
+	}
+
+	for _, commit := range commits {
+		fmt.Fprintf(&b, "%s %s (%s)\n", commit.Hash[:min(7, len(commit.Hash))], commit.Subject, util.FormatAgo(commit.Date))
+	}
+
+	return b.String()
+}
+
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+	return grove.Load()
+}
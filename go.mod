@@ -5,18 +5,16 @@ go 1.24
 require (
 	github.com/lmittmann/tint v1.1.1
 	github.com/mattn/go-colorable v0.1.14
-	github.com/otiai10/copy v1.14.1
+	github.com/mattn/go-isatty v0.0.20
 	github.com/samber/lo v1.50.0
 	github.com/spf13/cobra v1.9.1
+	github.com/spf13/pflag v1.0.6
+	golang.org/x/sys v0.29.0
+	golang.org/x/term v0.28.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
-	github.com/otiai10/mint v1.6.3 // indirect
-	github.com/spf13/pflag v1.0.6 // indirect
-	golang.org/x/sync v0.11.0 // indirect
-	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 )
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"runtime"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,20 +13,120 @@ type (
 	BranchPrefix      string
 )
 
+// HookEvent is an event during which hooks are executed.
+type HookEvent string
+
+const (
+	// HookEventBeforeCheckout runs in the repository root before a worktree is created or switched to.
+	HookEventBeforeCheckout HookEvent = "before-checkout"
+	// HookEventAfterCreate runs in the worktree only when it was newly created.
+	HookEventAfterCreate HookEvent = "after-create"
+	// HookEventAfterSwitch runs in the worktree only when it already existed.
+	HookEventAfterSwitch HookEvent = "after-switch"
+	// HookEventAfterCheckout runs in the worktree after every checkout.
+	HookEventAfterCheckout HookEvent = "after-checkout"
+	// HookEventBeforeRemove runs in the worktree before it's removed.
+	HookEventBeforeRemove HookEvent = "before-remove"
+	// HookEventAfterRemove runs in the repository root after a worktree is removed.
+	HookEventAfterRemove HookEvent = "after-remove"
+	// HookEventAfterPrune runs in the repository root after worktrees are pruned.
+	HookEventAfterPrune HookEvent = "after-prune"
+)
+
+// HookEvents returns every hook event in the order they're documented.
+func HookEvents() []HookEvent {
+	return []HookEvent{
+		HookEventBeforeCheckout,
+		HookEventAfterCreate,
+		HookEventAfterSwitch,
+		HookEventAfterCheckout,
+		HookEventBeforeRemove,
+		HookEventAfterRemove,
+		HookEventAfterPrune,
+	}
+}
+
+// ShellMode is how the hook shell is invoked.
+type ShellMode string
+
+const (
+	// ShellModePlain runs a non-interactive, non-login shell that doesn't load the user's rc files
+	ShellModePlain ShellMode = "plain"
+	// ShellModeLogin runs a login shell that loads the user's profile
+	ShellModeLogin ShellMode = "login"
+	// ShellModeInteractive runs an interactive shell that loads the user's rc files
+	ShellModeInteractive ShellMode = "interactive"
+)
+
 type Hooks struct {
-	Shell         string   `yaml:"shell"`
-	AfterCheckout []string `yaml:"after-checkout"`
+	Shell     string    `yaml:"shell"`
+	ShellMode ShellMode `yaml:"shell-mode"`
+	// Timeout is the maximum duration each hook may run for unless the
+	// hook sets its own, no limit when zero
+	Timeout        time.Duration `yaml:"timeout,omitempty"`
+	BeforeCheckout []Hook        `yaml:"before-checkout"`
+	AfterCreate    []Hook        `yaml:"after-create"`
+	AfterSwitch    []Hook        `yaml:"after-switch"`
+	AfterCheckout  []Hook        `yaml:"after-checkout"`
+	BeforeRemove   []Hook        `yaml:"before-remove"`
+	AfterRemove    []Hook        `yaml:"after-remove"`
+	AfterPrune     []Hook        `yaml:"after-prune"`
+}
+
+// ForEvent returns the hooks configured for the event.
+func (h Hooks) ForEvent(event HookEvent) []Hook {
+	switch event {
+	case HookEventBeforeCheckout:
+		return h.BeforeCheckout
+	case HookEventAfterCreate:
+		return h.AfterCreate
+	case HookEventAfterSwitch:
+		return h.AfterSwitch
+	case HookEventAfterCheckout:
+		return h.AfterCheckout
+	case HookEventBeforeRemove:
+		return h.BeforeRemove
+	case HookEventAfterRemove:
+		return h.AfterRemove
+	case HookEventAfterPrune:
+		return h.AfterPrune
+	default:
+		return nil
+	}
 }
 
 type BranchResolver struct {
 	BranchDelimiter     string                             `yaml:"branch-delimiter"`
 	BranchPrefixAliases map[BranchPrefixAlias]BranchPrefix `yaml:"prefix-aliases"`
+	// Tickets controls how ticket IDs in branch names are recognized and how
+	// branches are created for tickets
+	Tickets Tickets `yaml:"tickets"`
+}
+
+// PortRange is the range of ports allocated to worktrees, inclusive.
+type PortRange struct {
+	Start int `yaml:"start"`
+	End   int `yaml:"end"`
 }
 
 type Config struct {
-	WorkTreesDirectory string         `yaml:"worktrees-directory"`
-	BranchResolver     BranchResolver `yaml:"branch-resolver"`
-	Hooks              Hooks          `yaml:"hooks"`
+	WorkTreesDirectory string `yaml:"worktrees-directory"`
+	// PortRange is the range from which each worktree is allocated a port,
+	// available to hooks as {{.Port}} and $GROVE_PORT.
+	PortRange PortRange `yaml:"port-range"`
+	// BaseBranch is the branch new branches are created from. Defaults to
+	// the remote's HEAD when empty.
+	BaseBranch string `yaml:"base-branch"`
+	// PrefixBaseBranches overrides the base branch for branches with the
+	// specified prefix, e.g. `hotfix: release`.
+	PrefixBaseBranches map[BranchPrefix]string `yaml:"prefix-base-branches"`
+	BranchResolver     BranchResolver          `yaml:"branch-resolver"`
+	// BranchPolicy are the rules the names of new branches must follow.
+	BranchPolicy BranchPolicy `yaml:"branch-policy"`
+	// Seed controls how the files in the seed directory are placed in
+	// worktrees.
+	Seed  Seed  `yaml:"seed"`
+	Hooks Hooks `yaml:"hooks"`
 }
 
 func DefaultConfig() *Config {
@@ -45,13 +146,37 @@ func DefaultConfig() *Config {
 
 	return &Config{
 		WorkTreesDirectory: "./worktrees",
+		PortRange: PortRange{
+			Start: 3000,
+			End:   3999,
+		},
+		PrefixBaseBranches: map[BranchPrefix]string{},
 		BranchResolver: BranchResolver{
 			BranchPrefixAliases: map[BranchPrefixAlias]BranchPrefix{},
 			BranchDelimiter:     "/",
+			Tickets: Tickets{
+				Pattern: DefaultTicketPattern,
+			},
+		},
+		Seed: Seed{
+			Mode:     SeedModeAlwaysCopy,
+			Files:    []SeedFile{},
+			FromMain: []string{},
+			Clone: SeedClone{
+				Paths:    []string{},
+				Fallback: CloneFallbackCopy,
+			},
 		},
 		Hooks: Hooks{
-			Shell:         defaultShell,
-			AfterCheckout: []string{},
+			Shell:          defaultShell,
+			ShellMode:      ShellModePlain,
+			BeforeCheckout: []Hook{},
+			AfterCreate:    []Hook{},
+			AfterSwitch:    []Hook{},
+			AfterCheckout:  []Hook{},
+			BeforeRemove:   []Hook{},
+			AfterRemove:    []Hook{},
+			AfterPrune:     []Hook{},
 		},
 	}
 }
@@ -66,18 +191,19 @@ func (c *Config) Save(path string) error {
 	return os.WriteFile(path, marshaled, 0644)
 }
 
-// Load loads the config at the specified path into memory.
+// Load loads the config at the specified path into memory. Settings missing
+// from the file keep their default values.
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var cfg Config
-	err = yaml.Unmarshal(data, &cfg)
+	cfg := DefaultConfig()
+	err = yaml.Unmarshal(data, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return &cfg, nil
+	return cfg, nil
 }
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HookOnlyOn restricts a hook to new or existing worktrees.
+type HookOnlyOn string
+
+const (
+	HookOnlyOnAny      HookOnlyOn = ""
+	HookOnlyOnNew      HookOnlyOn = "new"
+	HookOnlyOnExisting HookOnlyOn = "existing"
+)
+
+// HookCondition restricts when a hook runs. Empty fields always match.
+type HookCondition struct {
+	// Branch is a glob matched against the branch name, e.g. `feature/*`
+	Branch string `yaml:"branch,omitempty"`
+	// OS is matched against the operating system, e.g. `linux`, `darwin` or `windows`
+	OS string `yaml:"os,omitempty"`
+}
+
+// Matches reports whether the condition matches the branch and operating
+// system.
+func (c HookCondition) Matches(branch string, goos string) bool {
+	if c.OS != "" && !strings.EqualFold(c.OS, goos) {
+		return false
+	}
+
+	if c.Branch != "" {
+		matched, err := path.Match(c.Branch, branch)
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Hook is a command run during a hook event. In the config file a hook is
+// either a plain command string or an object with additional options.
+type Hook struct {
+	// Name identifies the hook in output and in other hooks' depends-on,
+	// defaults to the command
+	Name string `yaml:"name,omitempty"`
+	// Run is the command to run
+	Run string `yaml:"run"`
+	// Template renders the command, working directory and environment
+	// variables as Go templates, e.g. `--port {{.Port}}`. Hooks are run as
+	// they are otherwise, so commands like `docker ps --format '{{.Names}}'`
+	// keep working.
+	Template bool `yaml:"template,omitempty"`
+	// Shell overrides the shell the command is run with
+	Shell string `yaml:"shell,omitempty"`
+	// ShellMode overrides how the shell is invoked
+	ShellMode ShellMode `yaml:"shell-mode,omitempty"`
+	// Stdin attaches grove's standard input to the command, which is
+	// otherwise detached so prompts can't hang checkout
+	Stdin bool `yaml:"stdin,omitempty"`
+	// WorkingDir is the directory the command is run in, relative to the
+	// directory the event runs in
+	WorkingDir string `yaml:"working-dir,omitempty"`
+	// Env are additional environment variables passed to the command
+	Env map[string]string `yaml:"env,omitempty"`
+	// Timeout is the maximum duration the command may run for, e.g. `5m`,
+	// overriding the global hook timeout
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// ContinueOnError runs the remaining hooks even if the command fails
+	ContinueOnError bool `yaml:"continue-on-error,omitempty"`
+	// OnlyOn restricts the hook to new or existing worktrees
+	OnlyOn HookOnlyOn `yaml:"only-on,omitempty"`
+	// When restricts the hook to matching branches and operating systems
+	When HookCondition `yaml:"when,omitempty"`
+	// Parallel runs the hook concurrently with the adjacent parallel hooks
+	Parallel bool `yaml:"parallel,omitempty"`
+	// DependsOn are the names of earlier hooks that must succeed before the
+	// hook runs
+	DependsOn []string `yaml:"depends-on,omitempty"`
+	// Background runs the hook detached from grove without waiting for it to
+	// finish. Its result is recorded under `.grove/hooks`.
+	Background bool `yaml:"background,omitempty"`
+}
+
+// DisplayName returns the name of the hook, or its command if it has no name.
+func (h Hook) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+
+	return h.Run
+}
+
+// hook has the same fields as Hook without its YAML methods.
+type hook Hook
+
+func (h *Hook) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*h = Hook{}
+		return value.Decode(&h.Run)
+	}
+
+	var decoded hook
+	err := value.Decode(&decoded)
+	if err != nil {
+		return err
+	}
+
+	if decoded.Run == "" {
+		return fmt.Errorf("line %d: hook is missing `run`", value.Line)
+	}
+
+	switch decoded.OnlyOn {
+	case HookOnlyOnAny, HookOnlyOnNew, HookOnlyOnExisting:
+	default:
+		return fmt.Errorf("line %d: invalid `only-on` value %q, must be %q or %q", value.Line, decoded.OnlyOn, HookOnlyOnNew, HookOnlyOnExisting)
+	}
+
+	*h = Hook(decoded)
+
+	return nil
+}
+
+func (h Hook) MarshalYAML() (any, error) {
+	// Hooks without options are written as plain strings
+	if h.isPlain() {
+		return h.Run, nil
+	}
+
+	return hook(h), nil
+}
+
+func (h Hook) isPlain() bool {
+	return h.Name == "" &&
+		!h.Template &&
+		h.Shell == "" &&
+		h.ShellMode == "" &&
+		!h.Stdin &&
+		h.WorkingDir == "" &&
+		len(h.Env) == 0 &&
+		h.Timeout == 0 &&
+		!h.ContinueOnError &&
+		h.OnlyOn == HookOnlyOnAny &&
+		h.When == HookCondition{} &&
+		!h.Parallel &&
+		len(h.DependsOn) == 0 &&
+		!h.Background
+}
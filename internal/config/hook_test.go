@@ -0,0 +1,106 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestHookUnmarshal(t *testing.T) {
+	data := `
+after-checkout:
+  - npm install
+  - run: npm run dev -- --port {{.Port}}
+    template: true
+    shell: /bin/bash
+    working-dir: web
+    env:
+      NODE_ENV: development
+    timeout: 5m
+    continue-on-error: true
+    only-on: new
+    when:
+      branch: feature/*
+      os: linux
+`
+
+	var hooks Hooks
+	err := yaml.Unmarshal([]byte(data), &hooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Hook{
+		{Run: "npm install"},
+		{
+			Run:             "npm run dev -- --port {{.Port}}",
+			Template:        true,
+			Shell:           "/bin/bash",
+			WorkingDir:      "web",
+			Env:             map[string]string{"NODE_ENV": "development"},
+			Timeout:         5 * time.Minute,
+			ContinueOnError: true,
+			OnlyOn:          HookOnlyOnNew,
+			When:            HookCondition{Branch: "feature/*", OS: "linux"},
+		},
+	}
+
+	if !reflect.DeepEqual(hooks.AfterCheckout, expected) {
+		t.Errorf("AfterCheckout = %+v, want %+v", hooks.AfterCheckout, expected)
+	}
+
+	marshaled, err := yaml.Marshal(hooks.AfterCheckout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(marshaled), "- npm install\n") {
+		t.Errorf("plain hook not marshaled as a string:\n%s", marshaled)
+	}
+}
+
+func TestHookUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "missing run", data: "- shell: /bin/sh"},
+		{name: "invalid only-on", data: "- run: echo\n  only-on: sometimes"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var hooks []Hook
+			if err := yaml.Unmarshal([]byte(tc.data), &hooks); err == nil {
+				t.Errorf("expected error unmarshaling %q", tc.data)
+			}
+		})
+	}
+}
+
+func TestHookConditionMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition HookCondition
+		branch    string
+		goos      string
+		want      bool
+	}{
+		{name: "empty", condition: HookCondition{}, branch: "main", goos: "linux", want: true},
+		{name: "branch glob", condition: HookCondition{Branch: "feature/*"}, branch: "feature/login", goos: "linux", want: true},
+		{name: "branch mismatch", condition: HookCondition{Branch: "feature/*"}, branch: "bugfix/login", goos: "linux", want: false},
+		{name: "os", condition: HookCondition{OS: "Darwin"}, branch: "main", goos: "darwin", want: true},
+		{name: "os mismatch", condition: HookCondition{OS: "windows"}, branch: "main", goos: "linux", want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.condition.Matches(tc.branch, tc.goos); got != tc.want {
+				t.Errorf("Matches(%q, %q) = %v, want %v", tc.branch, tc.goos, got, tc.want)
+			}
+		})
+	}
+}
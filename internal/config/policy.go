@@ -0,0 +1,21 @@
+package config
+
+// BranchPolicy are the rules the names of new branches must follow, in
+// addition to git's rules for ref names. The zero value allows any name git
+// accepts.
+type BranchPolicy struct {
+	// Prefixes are the prefixes new branches must start with, e.g. `feature`,
+	// any prefix is allowed when empty
+	Prefixes []string `yaml:"prefixes,omitempty"`
+	// RequireTicket requires names to contain a ticket ID matching the
+	// ticket pattern of the branch resolver
+	RequireTicket bool `yaml:"require-ticket,omitempty"`
+	// MaxLength is the maximum number of characters in a name, unlimited
+	// when zero
+	MaxLength int `yaml:"max-length,omitempty"`
+	// KebabCase requires every segment of a name to be lowercase words
+	// separated by dashes, e.g. `feature/fm-331-login`
+	KebabCase bool `yaml:"kebab-case,omitempty"`
+	// ForbiddenCharacters are characters names must not contain, e.g. `_#`
+	ForbiddenCharacters string `yaml:"forbidden-characters,omitempty"`
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SeedMode is how a seed file is placed in worktrees.
+type SeedMode string
+
+const (
+	// SeedModeAlwaysCopy copies the file on every checkout, overwriting changes made in the worktree
+	SeedModeAlwaysCopy SeedMode = "always-copy"
+	// SeedModeCopyOnce copies the file only if it doesn't exist in the worktree yet
+	SeedModeCopyOnce SeedMode = "copy-once"
+	// SeedModeSymlink links the file or directory to the seed, sharing it between worktrees
+	SeedModeSymlink SeedMode = "symlink"
+	// SeedModeHardlink hard links the file to the seed, sharing it between worktrees
+	SeedModeHardlink SeedMode = "hardlink"
+)
+
+func (m *SeedMode) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	err := value.Decode(&s)
+	if err != nil {
+		return err
+	}
+
+	switch mode := SeedMode(s); mode {
+	case SeedModeAlwaysCopy, SeedModeCopyOnce, SeedModeSymlink, SeedModeHardlink:
+		*m = mode
+		return nil
+	default:
+		return fmt.Errorf("line %d: invalid seed mode %q, must be %s, %s, %s or %s", value.Line, s, SeedModeAlwaysCopy, SeedModeCopyOnce, SeedModeSymlink, SeedModeHardlink)
+	}
+}
+
+// SeedFile sets the mode of the seed files matching a path.
+type SeedFile struct {
+	// Path is a glob matched against paths relative to the seed directory,
+	// e.g. `.env.*`. Matching a directory matches everything in it.
+	Path string   `yaml:"path"`
+	Mode SeedMode `yaml:"mode"`
+}
+
+type Seed struct {
+	// Mode is the mode of seed files that don't match any of Files
+	Mode SeedMode `yaml:"mode"`
+	// Files override the mode of matching seed files, the first match wins
+	Files []SeedFile `yaml:"files"`
+	// FromMain are globs of untracked and ignored files copied from the main
+	// worktree into new worktrees, e.g. `.env*` or `.idea/**`
+	FromMain []string `yaml:"from-main"`
+	// Clone are directories, typically dependencies, cloned from the main
+	// worktree into new worktrees using copy-on-write when possible
+	Clone SeedClone `yaml:"clone"`
+}
+
+// ModeFor returns the mode of the seed file at the slash-separated path
+// relative to the seed directory.
+func (s Seed) ModeFor(p string) SeedMode {
+	for _, file := range s.Files {
+		// Check the path and each of its parent directories
+		for candidate := p; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			if matched, err := path.Match(strings.TrimSuffix(file.Path, "/"), candidate); err == nil && matched {
+				return file.Mode
+			}
+		}
+	}
+
+	if s.Mode == "" {
+		return SeedModeAlwaysCopy
+	}
+
+	return s.Mode
+}
+
+// CloneFallback is how files are cloned when the file system doesn't support
+// copy-on-write.
+type CloneFallback string
+
+const (
+	// CloneFallbackCopy copies the files
+	CloneFallbackCopy CloneFallback = "copy"
+	// CloneFallbackHardlink hard links the files, sharing them between worktrees
+	CloneFallbackHardlink CloneFallback = "hardlink"
+)
+
+func (f *CloneFallback) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	err := value.Decode(&s)
+	if err != nil {
+		return err
+	}
+
+	switch fallback := CloneFallback(s); fallback {
+	case CloneFallbackCopy, CloneFallbackHardlink:
+		*f = fallback
+		return nil
+	default:
+		return fmt.Errorf("line %d: invalid clone fallback %q, must be %s or %s", value.Line, s, CloneFallbackCopy, CloneFallbackHardlink)
+	}
+}
+
+// SeedClone configures the directories cloned from the main worktree into
+// new worktrees.
+type SeedClone struct {
+	// Paths are globs of directories, e.g. `node_modules` or `**/target`
+	Paths []string `yaml:"paths"`
+	// Fallback is how files are cloned when the file system doesn't support
+	// copy-on-write
+	Fallback CloneFallback `yaml:"fallback"`
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSeedModeFor(t *testing.T) {
+	data := `
+mode: always-copy
+files:
+  - path: .env.*
+    mode: copy-once
+  - path: node_modules/.cache
+    mode: symlink
+  - path: "*.key"
+    mode: hardlink
+`
+
+	var seed Seed
+	err := yaml.Unmarshal([]byte(data), &seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want SeedMode
+	}{
+		{path: ".env.local", want: SeedModeCopyOnce},
+		{path: "node_modules/.cache", want: SeedModeSymlink},
+		{path: "node_modules/.cache/babel/x.json", want: SeedModeSymlink},
+		{path: "node_modules/left-pad", want: SeedModeAlwaysCopy},
+		{path: "server.key", want: SeedModeHardlink},
+		{path: "certs/server.key", want: SeedModeAlwaysCopy},
+		{path: ".vscode/settings.json", want: SeedModeAlwaysCopy},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			if got := seed.ModeFor(tc.path); got != tc.want {
+				t.Errorf("ModeFor(%q) = %s, want %s", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSeedModeUnmarshalError(t *testing.T) {
+	var seed Seed
+	if err := yaml.Unmarshal([]byte("files:\n  - path: .env\n    mode: move"), &seed); err == nil {
+		t.Error("expected error unmarshaling an invalid seed mode")
+	}
+}
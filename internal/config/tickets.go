@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultTicketPattern matches ticket IDs such as `FM-3311` or `abc2-12`.
+const DefaultTicketPattern TicketPattern = `[A-Z]{2,}[A-Z0-9]*-[0-9]+`
+
+// TicketPattern is a regular expression matching ticket IDs, e.g.
+// `[A-Z]+-\d+`. Only names matching it as is create ticket branches, while
+// the ticket IDs in existing branches are matched case-insensitively.
+type TicketPattern string
+
+func (p *TicketPattern) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	err := value.Decode(&s)
+	if err != nil {
+		return err
+	}
+
+	_, err = regexp.Compile(s)
+	if err != nil {
+		return fmt.Errorf("line %d: invalid ticket pattern %q: %v", value.Line, s, err)
+	}
+
+	*p = TicketPattern(s)
+	return nil
+}
+
+type Tickets struct {
+	// Pattern matches the ticket IDs in branch names, the default pattern is
+	// used when empty
+	Pattern TicketPattern `yaml:"pattern"`
+	// Prefix is the prefix of branches created for a ticket ID without one,
+	// e.g. `user1` creates `user1/fm-331-fix-login` for `FM-331`
+	Prefix string `yaml:"prefix,omitempty"`
+	// TitleProvider is a command printing the title of the ticket
+	// {{.TicketID}}, which is also available as $GROVE_TICKET_ID. The title
+	// names branches created for the ticket unless one is passed with
+	// --title.
+	TitleProvider string `yaml:"title-provider,omitempty"`
+}
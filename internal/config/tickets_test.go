@@ -0,0 +1,24 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestTicketPattern(t *testing.T) {
+	var tickets Tickets
+	err := yaml.Unmarshal([]byte(`pattern: '[A-Z]+-\d+'`), &tickets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tickets.Pattern != `[A-Z]+-\d+` {
+		t.Errorf("pattern = %q", tickets.Pattern)
+	}
+
+	err = yaml.Unmarshal([]byte(`pattern: '[A-Z+-\d+'`), &tickets)
+	if err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// commitFormat is the `git log` format parsed by Commit.Scan.
+const commitFormat = "%H%x09%cI%x09%s"
+
+type Commit struct {
+	Hash    string
+	Date    time.Time
+	Subject string
+}
+
+func (c Commit) String() string {
+	return fmt.Sprintf("%v %v", c.Hash, c.Subject)
+}
+
+func (c *Commit) Scan(v any) error {
+	switch val := v.(type) {
+	case string:
+		fields := strings.SplitN(strings.TrimSpace(val), "\t", 3)
+		if len(fields) != 3 {
+			return fmt.Errorf("invalid commit format")
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return fmt.Errorf("invalid commit date: %v", err)
+		}
+
+		c.Hash = fields[0]
+		c.Date = date
+		c.Subject = fields[2]
+	default:
+		return fmt.Errorf("invalid scan type")
+	}
+
+	return nil
+}
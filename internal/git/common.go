@@ -2,7 +2,11 @@ package git
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/samber/lo"
 )
@@ -13,12 +17,12 @@ func Pull(ctx context.Context) error {
 }
 
 func Fetch(ctx context.Context, args ...string) error {
-	_, err := execute(ctx, "fetch %v", strings.Join(args, " "))
+	_, err := execute(ctx, append([]string{"fetch"}, args...)...)
 	return err
 }
 
 func BranchExists(ctx context.Context, name string) bool {
-	output, err := execute(ctx, "branch --list %v", name)
+	output, err := execute(ctx, "branch", "--list", name)
 	if err != nil {
 		return false
 	}
@@ -27,7 +31,7 @@ func BranchExists(ctx context.Context, name string) bool {
 		return true
 	}
 
-	output, err = execute(ctx, "ls-remote --heads origin %v", name)
+	output, err = execute(ctx, "ls-remote", "--heads", "origin", name)
 	if err != nil {
 		return false
 	}
@@ -36,22 +40,433 @@ func BranchExists(ctx context.Context, name string) bool {
 }
 
 func ListBranches(ctx context.Context) ([]string, error) {
-	output, err := execute(ctx, "for-each-ref --format='%%(refname:short)' refs/heads/ refs/remotes/")
+	output, err := execute(ctx, "for-each-ref", "--format=%(refname:short)", "refs/heads/", "refs/remotes/")
 	if err != nil {
 		return nil, err
 	}
 
 	branches := strings.Split(output, "\n")
 	branches = lo.Map(branches, func(b string, _ int) string {
-		b = strings.Trim(b, " '")
+		b = strings.TrimSpace(b)
 		b = strings.TrimPrefix(b, "origin/")
 
 		return b
 	})
 	branches = lo.Uniq(branches)
 	branches = lo.Filter(branches, func(b string, _ int) bool {
-		return len(b) > 0 && b != "remote"
+		return len(b) > 0 && b != "remote" && b != "HEAD"
 	})
 
 	return branches, nil
 }
+
+// ListLocalBranches returns the names of the local branches.
+func ListLocalBranches(ctx context.Context) ([]string, error) {
+	output, err := execute(ctx, "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Compact(strings.Split(output, "\n")), nil
+}
+
+// DeleteBranch deletes the local branch. When force is set, the branch is
+// deleted even if it has not been merged.
+func DeleteBranch(ctx context.Context, name string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+
+	_, err := execute(ctx, "branch", flag, name)
+	return err
+}
+
+// HasUncommittedChanges reports whether the current worktree has staged,
+// unstaged or untracked changes.
+func HasUncommittedChanges(ctx context.Context) (bool, error) {
+	output, err := execute(ctx, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+
+	return len(strings.TrimSpace(output)) > 0, nil
+}
+
+// CountUnpushedCommits returns the number of commits reachable from HEAD
+// that do not exist on any remote.
+func CountUnpushedCommits(ctx context.Context) (int, error) {
+	output, err := execute(ctx, "rev-list", "--count", "HEAD", "--not", "--remotes")
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
+// GetUpstream returns the short name of the branch's upstream, or an empty
+// string if the branch does not track an upstream.
+func GetUpstream(ctx context.Context, branch string) (string, error) {
+	output, err := execute(ctx, "for-each-ref", "--format=%(upstream:short)", "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// CountAheadBehind returns the number of commits ref is ahead and behind
+// of the upstream ref.
+func CountAheadBehind(ctx context.Context, ref string, upstream string) (int, int, error) {
+	output, err := execute(ctx, "rev-list", "--left-right", "--count", ref+"..."+upstream)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %v", output)
+	}
+
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return ahead, behind, nil
+}
+
+// GetCommit returns the commit the ref points to.
+func GetCommit(ctx context.Context, ref string) (*Commit, error) {
+	output, err := execute(ctx, "log", "-1", "--format="+commitFormat, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	commit := &Commit{}
+	err = commit.Scan(output)
+	if err != nil {
+		return nil, err
+	}
+
+	return commit, nil
+}
+
+// EnableAutoSetupRemote makes the first `git push` of a branch without an
+// upstream push it to a branch of the same name and track it, unless
+// push.autoSetupRemote is already configured.
+func EnableAutoSetupRemote(ctx context.Context) error {
+	_, err := execute(ctx, "config", "--get", "push.autoSetupRemote")
+	if err == nil {
+		return nil
+	}
+
+	var gitErr *Error
+	if !errors.As(err, &gitErr) || gitErr.ExitCode != 1 {
+		return err
+	}
+
+	_, err = execute(ctx, "config", "push.autoSetupRemote", "true")
+	return err
+}
+
+// IsAncestor reports whether ref is an ancestor of, and therefore merged
+// into, base.
+func IsAncestor(ctx context.Context, ref string, base string) (bool, error) {
+	_, err := execute(ctx, "merge-base", "--is-ancestor", ref, base)
+	if err != nil {
+		var gitErr *Error
+		if errors.As(err, &gitErr) && gitErr.ExitCode == 1 {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetDefaultBranch returns the branch the remote's HEAD points to, e.g.
+// `main` for `origin/HEAD -> origin/main`.
+func GetDefaultBranch(ctx context.Context, remote string) (string, error) {
+	output, err := execute(ctx, "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "", fmt.Errorf("unable to determine default branch of %v, run `git remote set-head %v --auto`: %w", remote, remote, err)
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(output), remote+"/"), nil
+}
+
+// RefExists reports whether the fully qualified ref exists.
+func RefExists(ctx context.Context, ref string) bool {
+	_, err := execute(ctx, "rev-parse", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// IsValidBranchName reports whether git accepts the name as the name of a
+// branch, as checked by `git check-ref-format --branch`.
+func IsValidBranchName(ctx context.Context, name string) bool {
+	_, err := execute(ctx, "check-ref-format", "--branch", name)
+	return err == nil
+}
+
+// GetTopLevel returns the root directory of the worktree in the current
+// working directory.
+func GetTopLevel(ctx context.Context) (string, error) {
+	output, err := execute(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// ListTrackedFiles returns the slash-separated paths of the files tracked in
+// the worktree in the current working directory, relative to its root.
+func ListTrackedFiles(ctx context.Context) ([]string, error) {
+	output, err := execute(ctx, "ls-files", "-z", "--full-name", ":/")
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Compact(strings.Split(output, "\x00")), nil
+}
+
+// ListChangedFiles returns the slash-separated paths, relative to the root of
+// the current worktree, of files with staged, unstaged or untracked changes.
+func ListChangedFiles(ctx context.Context) ([]string, error) {
+	output, err := execute(ctx, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		files = append(files, entry[3:])
+
+		// Renames and copies are followed by the original path
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+	}
+
+	return files, nil
+}
+
+// CountStashes returns the number of stash entries created on each branch.
+func CountStashes(ctx context.Context) (map[string]int, error) {
+	output, err := execute(ctx, "stash", "list", "--format=%gs")
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, line := range strings.Split(output, "\n") {
+		// Stashes are described as "WIP on <branch>: ..." or "On <branch>: ..."
+		rest, ok := strings.CutPrefix(line, "WIP on ")
+		if !ok {
+			rest, ok = strings.CutPrefix(line, "On ")
+		}
+
+		if !ok {
+			continue
+		}
+
+		branch, _, ok := strings.Cut(rest, ":")
+		if ok {
+			counts[branch]++
+		}
+	}
+
+	return counts, nil
+}
+
+// Upstream is the remote branch a local branch tracks.
+type Upstream struct {
+	Name string
+	// Gone is set when the upstream no longer exists on the remote, e.g.
+	// because it was deleted after being merged
+	Gone bool
+}
+
+// ListUpstreams returns the upstream of each local branch that tracks one.
+func ListUpstreams(ctx context.Context) (map[string]Upstream, error) {
+	output, err := execute(ctx, "for-each-ref", "--format=%(refname:short)%09%(upstream:short)%09%(upstream:track)", "refs/heads/")
+	if err != nil {
+		return nil, err
+	}
+
+	upstreams := map[string]Upstream{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || fields[1] == "" {
+			continue
+		}
+
+		upstreams[fields[0]] = Upstream{
+			Name: fields[1],
+			Gone: fields[2] == "[gone]",
+		}
+	}
+
+	return upstreams, nil
+}
+
+// GetTree returns the hash of the tree of the commit the ref points to.
+func GetTree(ctx context.Context, ref string) (string, error) {
+	output, err := execute(ctx, "rev-parse", ref+"^{tree}")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// GetMergeBase returns the best common ancestor of the two refs.
+func GetMergeBase(ctx context.Context, ref string, other string) (string, error) {
+	output, err := execute(ctx, "merge-base", ref, other)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// GetDiffPatchID returns the stable patch-id of the changes between the two
+// refs, as if they were squashed into a single commit, or an empty string if
+// there are none.
+func GetDiffPatchID(ctx context.Context, ref string, other string) (string, error) {
+	diff, err := execute(ctx, "diff", "--no-color", "--no-ext-diff", ref, other)
+	if err != nil || diff == "" {
+		return "", err
+	}
+
+	ids, err := patchIDs(ctx, diff)
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+
+	return ids[0], nil
+}
+
+// ListPatchIDs returns the stable patch-ids of the commits reachable from
+// head but not upstream. Merge commits and commits without changes have none.
+func ListPatchIDs(ctx context.Context, upstream string, head string) ([]string, error) {
+	log, err := execute(ctx, "log", "-p", "--no-color", "--no-ext-diff", "--no-merges", upstream+".."+head)
+	if err != nil || log == "" {
+		return nil, err
+	}
+
+	return patchIDs(ctx, log)
+}
+
+// patchIDs returns the stable patch-ids of the patches, the output of
+// `git diff` or `git log -p`.
+func patchIDs(ctx context.Context, patches string) ([]string, error) {
+	output, err := execute(contextWithStdin(ctx, patches), "patch-id", "--stable")
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, line := range strings.Split(output, "\n") {
+		if id, _, ok := strings.Cut(line, " "); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// CountUnappliedCommits returns the number of commits reachable from head but
+// not upstream whose changes have no equivalent commit, compared by patch-id,
+// in upstream. Rebased and cherry-picked commits are considered applied.
+func CountUnappliedCommits(ctx context.Context, upstream string, head string) (int, error) {
+	output, err := execute(ctx, "cherry", upstream, head)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "+") {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// ListReflog returns the commits the ref pointed to, most recent first. It's
+// empty when the ref has no reflog.
+func ListReflog(ctx context.Context, ref string) ([]string, error) {
+	output, err := execute(ctx, "reflog", "show", "--format=%H", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Compact(strings.Split(strings.TrimSpace(output), "\n")), nil
+}
+
+// ListCommits returns up to limit commits reachable from ref, most recent
+// first.
+func ListCommits(ctx context.Context, ref string, limit int) ([]Commit, error) {
+	output, err := execute(ctx, "log", "-n", strconv.Itoa(limit), "--format="+commitFormat, ref, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range lo.Compact(strings.Split(output, "\n")) {
+		var commit Commit
+		err = commit.Scan(line)
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// ListBranchDates returns the date of the latest commit of each local and
+// remote branch. Remote branches are named like local ones, as in
+// ListBranches, and the more recent date wins.
+func ListBranchDates(ctx context.Context) (map[string]time.Time, error) {
+	output, err := execute(ctx, "for-each-ref", "--format=%(refname:short)%09%(committerdate:iso-strict)", "refs/heads/", "refs/remotes/")
+	if err != nil {
+		return nil, err
+	}
+
+	dates := map[string]time.Time{}
+	for _, line := range strings.Split(output, "\n") {
+		name, value, ok := strings.Cut(line, "\t")
+		if !ok || value == "" {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit date of %s: %w", name, err)
+		}
+
+		name = strings.TrimPrefix(name, "origin/")
+		if date.After(dates[name]) {
+			dates[name] = date
+		}
+	}
+
+	return dates, nil
+}
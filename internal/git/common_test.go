@@ -0,0 +1,73 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeRunner responds to git commands with canned output, keyed by the
+// space-joined arguments.
+type fakeRunner map[string]string
+
+func (f fakeRunner) Run(ctx context.Context, args ...string) (*Result, error) {
+	if out, ok := f[strings.Join(args, " ")]; ok {
+		return &Result{Stdout: out}, nil
+	}
+
+	return nil, &Error{Args: args, ExitCode: 1, Err: errors.New("unexpected command")}
+}
+
+func TestListChangedFiles(t *testing.T) {
+	ctx := ContextWithRunner(context.Background(), fakeRunner{
+		"-C /repo status --porcelain -z --untracked-files=all": " M main.go\x00R  new.go\x00old.go\x00?? dir/with space.txt\x00",
+	})
+	ctx = ContextWithDir(ctx, "/repo")
+
+	files, err := ListChangedFiles(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"main.go", "new.go", "dir/with space.txt"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("ListChangedFiles() = %v, want %v", files, expected)
+	}
+}
+
+func TestCountStashes(t *testing.T) {
+	ctx := ContextWithRunner(context.Background(), fakeRunner{
+		"stash list --format=%gs": "WIP on feature/x: abc123 subject\nOn feature/x: message\nOn main: other\n",
+	})
+
+	counts, err := CountStashes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{"feature/x": 2, "main": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("CountStashes() = %v, want %v", counts, expected)
+	}
+}
+
+func TestListUpstreams(t *testing.T) {
+	ctx := ContextWithRunner(context.Background(), fakeRunner{
+		"for-each-ref --format=%(refname:short)%09%(upstream:short)%09%(upstream:track) refs/heads/": "main\torigin/main\t[behind 1]\nfeature/x\torigin/feature/x\t[gone]\nlocal\t\t\n",
+	})
+
+	upstreams, err := ListUpstreams(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]Upstream{
+		"main":      {Name: "origin/main"},
+		"feature/x": {Name: "origin/feature/x", Gone: true},
+	}
+	if !reflect.DeepEqual(upstreams, expected) {
+		t.Errorf("ListUpstreams() = %v, want %v", upstreams, expected)
+	}
+}
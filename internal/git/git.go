@@ -2,7 +2,6 @@ package git
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"os/exec"
 	"strings"
@@ -14,7 +13,7 @@ func ValidateGitInstallation() error {
 }
 
 func IsGitRepository(ctx context.Context) (bool, error) {
-	res, err := execute(ctx, "rev-parse --is-inside-work-tree")
+	res, err := execute(ctx, "rev-parse", "--is-inside-work-tree")
 	if err != nil {
 		return false, err
 	}
@@ -22,14 +21,41 @@ func IsGitRepository(ctx context.Context) (bool, error) {
 	return strings.TrimSpace(res) == "true", nil
 }
 
-func execute(ctx context.Context, format string, args ...any) (string, error) {
-	slog.Debug("executing git command", slog.String("command", fmt.Sprintf(format, args...)))
+const dirContextKey = contextKey("dir")
 
-	cmdFormatted := fmt.Sprintf(format, args...)
-	cmd := exec.CommandContext(ctx, "git", strings.Split(cmdFormatted, " ")...)
-	output, err := cmd.CombinedOutput()
+// ContextWithDir returns a context in which git commands are executed in the
+// specified directory, as with `git -C <dir>`, rather than the current
+// working directory. Unlike changing the working directory, it's safe to use
+// from multiple goroutines.
+func ContextWithDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, dirContextKey, dir)
+}
+
+// Execute runs git with the specified arguments.
+func Execute(ctx context.Context, args ...string) (*Result, error) {
+	if dir, ok := ctx.Value(dirContextKey).(string); ok && dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+
+	slog.Debug("executing git command", slog.Any("args", args))
+
+	res, err := runnerFromContext(ctx).Run(ctx, args...)
+	if err != nil {
+		slog.Debug("git command failed", slog.Any("args", args), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	slog.Debug("git command output", slog.Any("args", args), slog.String("output", res.Stdout))
+
+	return res, nil
+}
 
-	slog.Debug("git command output", slog.String("command", cmdFormatted), slog.String("output", string(output)))
+// execute runs git with the specified arguments and returns its stdout.
+func execute(ctx context.Context, args ...string) (string, error) {
+	res, err := Execute(ctx, args...)
+	if err != nil {
+		return "", err
+	}
 
-	return string(output), err
+	return res.Stdout, nil
 }
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type contextKey string
+
+const (
+	runnerContextKey = contextKey("runner")
+	stdinContextKey  = contextKey("stdin")
+)
+
+// Runner executes git commands. Arguments are passed to git as-is and are
+// never split or re-quoted.
+type Runner interface {
+	Run(ctx context.Context, args ...string) (*Result, error)
+}
+
+// Result is the output of a successful git command.
+type Result struct {
+	Stdout string
+	Stderr string
+}
+
+// Error is returned when a git command fails to run or exits with a
+// non-zero exit code.
+type Error struct {
+	// Args are the arguments git was executed with
+	Args []string
+	// ExitCode is the exit code of the git process, -1 if it did not exit
+	ExitCode int
+	// Stderr is the standard error output of the git process
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	msg := strings.TrimSpace(e.Stderr)
+	if msg == "" {
+		msg = e.Err.Error()
+	}
+
+	return fmt.Sprintf("git %v: %v", strings.Join(e.Args, " "), msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ExecRunner runs git commands using the git executable on the PATH in the
+// current working directory.
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, args ...string) (*Result, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if stdin, ok := ctx.Value(stdinContextKey).(string); ok {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	err := cmd.Run()
+	if err != nil {
+		exitCode := -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+
+		return nil, &Error{
+			Args:     args,
+			ExitCode: exitCode,
+			Stderr:   stderr.String(),
+			Err:      err,
+		}
+	}
+
+	return &Result{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}, nil
+}
+
+// ContextWithRunner returns a context in which git commands are executed
+// by the specified runner instead of the git executable.
+func ContextWithRunner(ctx context.Context, runner Runner) context.Context {
+	return context.WithValue(ctx, runnerContextKey, runner)
+}
+
+// contextWithStdin returns a context in which git commands read the input
+// from stdin, e.g. `git patch-id`.
+func contextWithStdin(ctx context.Context, input string) context.Context {
+	return context.WithValue(ctx, stdinContextKey, input)
+}
+
+func runnerFromContext(ctx context.Context) Runner {
+	if runner, ok := ctx.Value(runnerContextKey).(Runner); ok {
+		return runner
+	}
+
+	return ExecRunner{}
+}
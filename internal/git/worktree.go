@@ -3,38 +3,41 @@ package git
 import (
 	"context"
 	"errors"
-	"fmt"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/samber/lo"
 )
 
 var (
-	ErrWorkTreeNotFound       = errors.New("not found")
-	workTreeListFormatPattern = regexp.MustCompile(`^(.*)\s+([a-f0-9]+)\s+\[(.*)\]`)
+	ErrWorkTreeNotFound = errors.New("not found")
 )
 
 // ExecuteWorkTree runs a `git worktree` command with the specified arguments.
-func ExecuteWorkTree(ctx context.Context, cmd string, args ...any) (string, error) {
-	return execute(ctx, fmt.Sprintf("worktree %v", cmd), args...)
+func ExecuteWorkTree(ctx context.Context, args ...string) (*Result, error) {
+	return Execute(ctx, append([]string{"worktree"}, args...)...)
 }
 
 func ListWorkTrees(ctx context.Context) ([]WorkTree, error) {
-	output, err := ExecuteWorkTree(ctx, "list")
+	output, err := execute(ctx, "worktree", "list", "--porcelain", "-z")
 	if err != nil {
 		return nil, err
 	}
 
-	wts := strings.Split(output, "\n")
+	return parseWorkTrees(output), nil
+}
+
+// parseWorkTrees parses the output of `git worktree list --porcelain -z`.
+func parseWorkTrees(output string) []WorkTree {
+	// Records are separated by an empty attribute
+	wts := strings.Split(output, "\x00\x00")
 
 	return lo.FilterMap(wts, func(v string, _ int) (WorkTree, bool) {
 		wt := &WorkTree{}
 		err := wt.Scan(v)
 
 		return *wt, err == nil
-	}), nil
+	})
 }
 
 func FindWorkTree(ctx context.Context, branch string) (*WorkTree, error) {
@@ -55,7 +58,7 @@ func FindWorkTree(ctx context.Context, branch string) (*WorkTree, error) {
 func CreateWorkTreeFromBranch(ctx context.Context, worktreesPath string, branch string) (*WorkTree, error) {
 	worktreePath := filepath.Join(worktreesPath, branch)
 
-	_, err := ExecuteWorkTree(ctx, "add %v %v", worktreePath, branch)
+	_, err := ExecuteWorkTree(ctx, "add", worktreePath, branch)
 	if err != nil {
 		return nil, err
 	}
@@ -63,13 +66,46 @@ func CreateWorkTreeFromBranch(ctx context.Context, worktreesPath string, branch
 	return FindWorkTree(ctx, branch)
 }
 
-func CreateWorkTreeFromNewBranch(ctx context.Context, worktreesPath string, branch string) (*WorkTree, error) {
+// CreateWorkTreeFromNewBranch creates a new branch from the start point and
+// adds a worktree for it. The start point can be any commit-ish.
+func CreateWorkTreeFromNewBranch(ctx context.Context, worktreesPath string, branch string, startPoint string) (*WorkTree, error) {
 	worktreePath := filepath.Join(worktreesPath, branch)
 
-	_, err := ExecuteWorkTree(ctx, "add -b %v %v main", branch, worktreePath)
+	_, err := ExecuteWorkTree(ctx, "add", "--no-track", "-b", branch, worktreePath, startPoint)
 	if err != nil {
 		return nil, err
 	}
 
 	return FindWorkTree(ctx, branch)
 }
+
+// RemoveWorkTree removes the worktree located at the specified path. When
+// force is set, the worktree is removed even if it has local modifications.
+func RemoveWorkTree(ctx context.Context, path string, force bool) error {
+	args := []string{"remove"}
+	if force {
+		args = append(args, "--force")
+	}
+
+	_, err := ExecuteWorkTree(ctx, append(args, path)...)
+	return err
+}
+
+// PruneWorkTrees prunes the administrative files of worktrees that no longer
+// exist on disk. When dryRun is set, nothing is removed.
+func PruneWorkTrees(ctx context.Context, dryRun bool, verbose bool, expire string) (*Result, error) {
+	args := []string{"prune"}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+
+	if verbose {
+		args = append(args, "--verbose")
+	}
+
+	if expire != "" {
+		args = append(args, "--expire", expire)
+	}
+
+	return ExecuteWorkTree(ctx, args...)
+}
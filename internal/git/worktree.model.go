@@ -6,26 +6,65 @@ import (
 )
 
 type WorkTree struct {
-	Path   string
-	Head   string
-	Branch string
+	Path string
+	Head string
+	// Ref is the full refname of the checked out branch, e.g. refs/heads/main
+	Ref string
+	// Branch is the short name of the checked out branch, empty if detached
+	Branch         string
+	Bare           bool
+	Detached       bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool
+	PrunableReason string
 }
 
 func (w WorkTree) String() string {
-	return fmt.Sprintf("%v %v [%v]", w.Path, w.Head, w.Branch)
+	switch {
+	case w.Bare:
+		return fmt.Sprintf("%v (bare)", w.Path)
+	case w.Detached:
+		return fmt.Sprintf("%v %v (detached HEAD)", w.Path, w.Head)
+	default:
+		return fmt.Sprintf("%v %v [%v]", w.Path, w.Head, w.Branch)
+	}
 }
 
+// Scan parses a single worktree record from the output of
+// `git worktree list --porcelain -z`, in which each attribute is
+// terminated by a NUL byte.
 func (w *WorkTree) Scan(v any) error {
 	switch val := v.(type) {
 	case string:
-		matches := workTreeListFormatPattern.FindStringSubmatch(val)
-		if len(matches) != 4 {
+		attrs := strings.Split(strings.Trim(val, "\x00\n"), "\x00")
+		if len(attrs) == 0 || !strings.HasPrefix(attrs[0], "worktree ") {
 			return fmt.Errorf("invalid worktree format")
 		}
 
-		w.Path = strings.TrimSpace(matches[1])
-		w.Head = strings.TrimSpace(matches[2])
-		w.Branch = strings.TrimSpace(matches[3])
+		for _, attr := range attrs {
+			key, value, _ := strings.Cut(attr, " ")
+
+			switch key {
+			case "worktree":
+				w.Path = value
+			case "HEAD":
+				w.Head = value
+			case "branch":
+				w.Ref = value
+				w.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "bare":
+				w.Bare = true
+			case "detached":
+				w.Detached = true
+			case "locked":
+				w.Locked = true
+				w.LockReason = value
+			case "prunable":
+				w.Prunable = true
+				w.PrunableReason = value
+			}
+		}
 	default:
 		return fmt.Errorf("invalid scan type")
 	}
@@ -0,0 +1,45 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWorkTrees(t *testing.T) {
+	output := "worktree /repo\x00HEAD abc123\x00branch refs/heads/main\x00\x00" +
+		"worktree /repo/worktrees/with space [x]\x00HEAD def456\x00branch refs/heads/feature/x\x00locked wip stuff\x00\x00" +
+		"worktree /repo/worktrees/detached\x00HEAD 789abc\x00detached\x00prunable gitdir file points to non-existent location\x00\x00" +
+		"worktree /repo.git\x00bare\x00\x00"
+
+	expected := []WorkTree{
+		{Path: "/repo", Head: "abc123", Ref: "refs/heads/main", Branch: "main"},
+		{Path: "/repo/worktrees/with space [x]", Head: "def456", Ref: "refs/heads/feature/x", Branch: "feature/x", Locked: true, LockReason: "wip stuff"},
+		{Path: "/repo/worktrees/detached", Head: "789abc", Detached: true, Prunable: true, PrunableReason: "gitdir file points to non-existent location"},
+		{Path: "/repo.git", Bare: true},
+	}
+
+	wts := parseWorkTrees(output)
+	if !reflect.DeepEqual(wts, expected) {
+		t.Errorf("parseWorkTrees() = %+v, want %+v", wts, expected)
+	}
+}
+
+func TestCreateWorkTreeFromNewBranch(t *testing.T) {
+	// The new branch must not track the start point, otherwise pushing would
+	// target the base branch
+	ctx := ContextWithRunner(context.Background(), fakeRunner{
+		"worktree add --no-track -b feature/x " + filepath.Join("worktrees", "feature/x") + " origin/main": "",
+		"worktree list --porcelain -z": "worktree /repo/worktrees/feature/x\x00HEAD abc\x00branch refs/heads/feature/x\x00\x00",
+	})
+
+	wt, err := CreateWorkTreeFromNewBranch(ctx, "worktrees", "feature/x", "origin/main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wt.Branch != "feature/x" {
+		t.Errorf("CreateWorkTreeFromNewBranch() branch = %q, want feature/x", wt.Branch)
+	}
+}
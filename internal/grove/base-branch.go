@@ -0,0 +1,59 @@
+package grove
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+// defaultRemote is the remote used to resolve the default base branch
+const defaultRemote = "origin"
+
+// baseBranch returns the branch the specified branch should be created from
+// and merged into. Prefix specific base branches take precedence over the
+// configured base branch, which in turn takes precedence over the remote's
+// HEAD.
+func (grove *Grove) baseBranch(ctx context.Context, branch string) (string, error) {
+	delimiter := grove.Config.BranchResolver.BranchDelimiter
+
+	var base, matched string
+	for prefix, prefixBase := range grove.Config.PrefixBaseBranches {
+		p := string(prefix)
+		if strings.HasPrefix(branch, p+delimiter) && len(p) > len(matched) {
+			base = prefixBase
+			matched = p
+		}
+	}
+
+	if base != "" {
+		slog.DebugContext(ctx, "using prefix base branch", slog.String("branch", branch), slog.String("prefix", matched), slog.String("base", base))
+		return base, nil
+	}
+
+	if grove.Config.BaseBranch != "" {
+		return grove.Config.BaseBranch, nil
+	}
+
+	return git.GetDefaultBranch(ctx, defaultRemote)
+}
+
+// baseRef returns the ref new branches should be created from, preferring the
+// freshly fetched remote-tracking branch of the base branch over the local
+// branch, which may be out of date or not exist at all.
+func (grove *Grove) baseRef(ctx context.Context, branch string) (string, error) {
+	base, err := grove.baseBranch(ctx, branch)
+	if err != nil {
+		return "", err
+	}
+
+	remoteRef := defaultRemote + "/" + base
+	if git.RefExists(ctx, "refs/remotes/"+remoteRef) {
+		return remoteRef, nil
+	}
+
+	slog.DebugContext(ctx, "base branch does not exist on remote, using local branch", slog.String("base", base))
+
+	return base, nil
+}
@@ -0,0 +1,91 @@
+package grove
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+func TestBaseBranch(t *testing.T) {
+	runner := &fakeRunner{responses: map[string]string{
+		"symbolic-ref --short refs/remotes/origin/HEAD": "origin/develop\n",
+	}}
+	ctx := git.ContextWithRunner(context.Background(), runner)
+
+	tests := []struct {
+		name     string
+		base     string
+		prefixes map[config.BranchPrefix]string
+		branch   string
+		out      string
+	}{
+		{name: "remote head", branch: "feature/x", out: "develop"},
+		{name: "configured", base: "trunk", branch: "feature/x", out: "trunk"},
+		{name: "prefix", base: "trunk", prefixes: map[config.BranchPrefix]string{"hotfix": "release"}, branch: "hotfix/x", out: "release"},
+		{name: "prefix not matched", base: "trunk", prefixes: map[config.BranchPrefix]string{"hotfix": "release"}, branch: "hotfixes", out: "trunk"},
+		{name: "longest prefix", prefixes: map[config.BranchPrefix]string{"a": "one", "a/b": "two"}, branch: "a/b/c", out: "two"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			grove := Grove{
+				Config: &config.Config{
+					BaseBranch:         tc.base,
+					PrefixBaseBranches: tc.prefixes,
+					BranchResolver:     config.BranchResolver{BranchDelimiter: "/"},
+				},
+			}
+
+			base, err := grove.baseBranch(ctx, tc.branch)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if base != tc.out {
+				t.Errorf("'%v' -> '%v' != '%v'", tc.branch, base, tc.out)
+			}
+		})
+	}
+}
+
+func TestBaseRef(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]string
+		out       string
+	}{
+		{
+			name: "remote",
+			responses: map[string]string{
+				"rev-parse --verify --quiet refs/remotes/origin/develop": "abc\n",
+			},
+			out: "origin/develop",
+		},
+		{name: "local only", out: "develop"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := &fakeRunner{responses: tc.responses}
+			ctx := git.ContextWithRunner(context.Background(), runner)
+
+			grove := Grove{
+				Config: &config.Config{
+					BaseBranch:     "develop",
+					BranchResolver: config.BranchResolver{BranchDelimiter: "/"},
+				},
+			}
+
+			ref, err := grove.baseRef(ctx, "feature/x")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ref != tc.out {
+				t.Errorf("baseRef() = %q, want %q", ref, tc.out)
+			}
+		})
+	}
+}
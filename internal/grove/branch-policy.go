@@ -0,0 +1,258 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+)
+
+var ErrInvalidBranchName = errors.New("invalid branch name")
+
+// kebabCasePattern matches lowercase words separated by dashes.
+var kebabCasePattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// validateBranchName checks the name of a branch about to be created against
+// the branch policy and git's rules for ref names. The error lists every rule
+// the name breaks and suggests a name that follows them, if one can be
+// derived from it.
+func (grove *Grove) validateBranchName(ctx context.Context, branch string) error {
+	violations := grove.branchViolations(ctx, branch)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	err := fmt.Errorf("%w %q, it %s", ErrInvalidBranchName, branch, strings.Join(violations, ", "))
+
+	suggestion := grove.suggestBranchName(branch)
+	if suggestion == branch || len(grove.branchViolations(ctx, suggestion)) > 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w, did you mean %q?", err, suggestion)
+}
+
+// branchViolations describes the rules the branch name breaks.
+func (grove *Grove) branchViolations(ctx context.Context, branch string) []string {
+	policy := grove.Config.BranchPolicy
+	delimiter := grove.Config.BranchResolver.BranchDelimiter
+
+	var violations []string
+	if !git.IsValidBranchName(ctx, branch) {
+		violations = append(violations, "isn't a valid git branch name")
+	}
+
+	if len(policy.Prefixes) > 0 && !grove.hasAllowedPrefix(branch) {
+		prefixes := lo.Map(policy.Prefixes, func(p string, _ int) string { return p + delimiter })
+		violations = append(violations, "must start with one of "+strings.Join(prefixes, ", "))
+	}
+
+	if policy.RequireTicket && grove.ticketID(branch) == "" {
+		violations = append(violations, "must contain a ticket ID")
+	}
+
+	if policy.MaxLength > 0 && utf8.RuneCountInString(branch) > policy.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters long", policy.MaxLength))
+	}
+
+	if policy.KebabCase && !isKebabCase(branch, delimiter) {
+		violations = append(violations, "must be lowercase words separated by dashes")
+	}
+
+	var forbidden []string
+	for _, r := range policy.ForbiddenCharacters {
+		if strings.ContainsRune(branch, r) {
+			forbidden = append(forbidden, fmt.Sprintf("%q", r))
+		}
+	}
+
+	if len(forbidden) > 0 {
+		violations = append(violations, "must not contain "+strings.Join(forbidden, ", "))
+	}
+
+	return violations
+}
+
+// hasAllowedPrefix reports whether the branch starts with one of the
+// prefixes of the branch policy and has a name after it.
+func (grove *Grove) hasAllowedPrefix(branch string) bool {
+	delimiter := grove.Config.BranchResolver.BranchDelimiter
+
+	return slices.ContainsFunc(grove.Config.BranchPolicy.Prefixes, func(prefix string) bool {
+		return len(branch) > len(prefix+delimiter) && strings.HasPrefix(branch, prefix+delimiter)
+	})
+}
+
+func isKebabCase(branch string, delimiter string) bool {
+	segments := []string{branch}
+	if delimiter != "" {
+		segments = strings.Split(branch, delimiter)
+	}
+
+	return lo.EveryBy(segments, kebabCasePattern.MatchString)
+}
+
+// suggestBranchName corrects the branch name as far as possible: misspelled
+// or missing prefixes are replaced by the closest allowed one, characters
+// that aren't allowed are replaced by dashes and long names are shortened.
+// Missing ticket IDs can't be corrected.
+func (grove *Grove) suggestBranchName(branch string) string {
+	policy := grove.Config.BranchPolicy
+	delimiter := grove.Config.BranchResolver.BranchDelimiter
+
+	name := branch
+	if len(policy.Prefixes) > 0 && !grove.hasAllowedPrefix(name) {
+		name = grove.correctPrefix(name)
+	}
+
+	segments := []string{name}
+	if delimiter != "" {
+		segments = strings.Split(name, delimiter)
+	}
+
+	segments = lo.FilterMap(segments, func(segment string, _ int) (string, bool) {
+		segment = strings.Map(func(r rune) rune {
+			return lo.Ternary(strings.ContainsRune(policy.ForbiddenCharacters, r), '-', r)
+		}, segment)
+
+		if policy.KebabCase {
+			segment = util.Slugify(segment)
+		}
+
+		segment = sanitizeRefSegment(segment)
+		return segment, segment != ""
+	})
+
+	name = strings.Join(segments, delimiter)
+	if policy.MaxLength > 0 {
+		name = shortenBranchName(name, policy.MaxLength, delimiter)
+	}
+
+	return name
+}
+
+// correctPrefix replaces the prefix of the branch with the allowed prefix it
+// most likely misspells, e.g. `featrue/login` becomes `feature/login`. The
+// default prefix is prepended to branches without a similar prefix.
+func (grove *Grove) correctPrefix(branch string) string {
+	prefixes := grove.Config.BranchPolicy.Prefixes
+	delimiter := grove.Config.BranchResolver.BranchDelimiter
+
+	defaultPrefix := prefixes[0]
+	if prefix := grove.Config.BranchResolver.Tickets.Prefix; slices.Contains(prefixes, prefix) {
+		defaultPrefix = prefix
+	}
+
+	if delimiter == "" {
+		return defaultPrefix + branch
+	}
+
+	segments := strings.Split(branch, delimiter)
+
+	best, bestDistance, bestSegments := "", -1, 0
+	for _, prefix := range prefixes {
+		// Prefixes may span several segments, e.g. `team/feature`
+		n := strings.Count(prefix, delimiter) + 1
+		if n >= len(segments) {
+			continue
+		}
+
+		distance := editDistance(strings.Join(segments[:n], delimiter), prefix)
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance, bestSegments = prefix, distance, n
+		}
+	}
+
+	if bestDistance < 0 || bestDistance > max(2, utf8.RuneCountInString(best)/3) {
+		return defaultPrefix + delimiter + branch
+	}
+
+	return best + delimiter + strings.Join(segments[bestSegments:], delimiter)
+}
+
+// sanitizeRefSegment replaces the characters git doesn't allow in a segment
+// of a ref name with dashes and trims the dots and dashes it can't start or
+// end with.
+func sanitizeRefSegment(segment string) string {
+	segment = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.IsSpace(r) || strings.ContainsRune("~^:?*[\\", r) {
+			return '-'
+		}
+
+		return r
+	}, segment)
+
+	segment = strings.ReplaceAll(segment, "@{", "-")
+	for strings.Contains(segment, "..") {
+		segment = strings.ReplaceAll(segment, "..", ".")
+	}
+
+	for strings.Contains(segment, "--") {
+		segment = strings.ReplaceAll(segment, "--", "-")
+	}
+
+	segment = strings.TrimSuffix(strings.Trim(segment, ".-"), ".lock")
+	return strings.Trim(segment, ".-")
+}
+
+// shortenBranchName shortens the name to at most maxLength characters,
+// cutting at a dash after the last delimiter when possible so words aren't
+// split.
+func shortenBranchName(name string, maxLength int, delimiter string) string {
+	runes := []rune(name)
+	if len(runes) <= maxLength {
+		return name
+	}
+
+	short := string(runes[:maxLength])
+	if runes[maxLength] != '-' {
+		start := 0
+		if delimiter != "" {
+			start = strings.LastIndex(short, delimiter) + len(delimiter)
+		}
+
+		if i := strings.LastIndex(short, "-"); i > start {
+			short = short[:i]
+		}
+	}
+
+	short = strings.TrimRight(short, "-.")
+	if delimiter != "" {
+		short = strings.TrimSuffix(short, delimiter)
+	}
+
+	return short
+}
+
+// editDistance returns the Levenshtein distance between the strings, the
+// number of runes that must be inserted, deleted or replaced to turn one
+// into the other.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := range ra {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i + 1
+		for j := range rb {
+			cost := lo.Ternary(ra[i] == rb[j], 0, 1)
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+
+		prev = cur
+	}
+
+	return prev[len(rb)]
+}
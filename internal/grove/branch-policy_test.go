@@ -0,0 +1,88 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestValidateBranchName(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     config.BranchPolicy
+		branch     string
+		suggestion string
+		valid      bool
+	}{
+		{name: "no policy", branch: "anything_Goes/x", valid: true},
+		{name: "invalid ref", branch: "feature/a..b", suggestion: "feature/a.b"},
+		{name: "invalid characters", branch: "feature/add login?", suggestion: "feature/add-login"},
+		{name: "lock suffix", branch: "feature/x.lock", suggestion: "feature/x"},
+		{name: "allowed prefix", policy: config.BranchPolicy{Prefixes: []string{"feature", "bugfix"}}, branch: "bugfix/x", valid: true},
+		{name: "misspelled prefix", policy: config.BranchPolicy{Prefixes: []string{"feature", "bugfix"}}, branch: "featrue/x", suggestion: "feature/x"},
+		{name: "missing prefix", policy: config.BranchPolicy{Prefixes: []string{"feature", "user1"}}, branch: "x", suggestion: "user1/x"},
+		{name: "unrelated prefix", policy: config.BranchPolicy{Prefixes: []string{"feature"}}, branch: "wip/x", suggestion: "feature/wip/x"},
+		{name: "prefix without name", policy: config.BranchPolicy{Prefixes: []string{"feature"}}, branch: "feature/", suggestion: ""},
+		{name: "ticket", policy: config.BranchPolicy{RequireTicket: true}, branch: "u/fm-1-x", valid: true},
+		{name: "missing ticket", policy: config.BranchPolicy{RequireTicket: true}, branch: "u/x"},
+		{name: "too long", policy: config.BranchPolicy{MaxLength: 20}, branch: "user1/fm-1-fix-the-login-page", suggestion: "user1/fm-1-fix-the"},
+		{name: "kebab case", policy: config.BranchPolicy{KebabCase: true}, branch: "user1/FM-1_Fix Login", suggestion: "user1/fm-1-fix-login"},
+		{name: "forbidden characters", policy: config.BranchPolicy{ForbiddenCharacters: "_#"}, branch: "user1/fix_#login", suggestion: "user1/fix-login"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.BranchPolicy = tc.policy
+			cfg.BranchResolver.Tickets.Prefix = "user1"
+
+			grove := Grove{Config: cfg}
+			err := grove.validateBranchName(context.Background(), tc.branch)
+			if tc.valid {
+				if err != nil {
+					t.Fatalf("validateBranchName(%q) error = %v", tc.branch, err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidBranchName) {
+				t.Fatalf("validateBranchName(%q) error = %v, want %v", tc.branch, err, ErrInvalidBranchName)
+			}
+
+			_, suggestion, suggested := strings.Cut(err.Error(), "did you mean ")
+			if tc.suggestion == "" {
+				if suggested {
+					t.Errorf("validateBranchName(%q) error = %v, want no suggestion", tc.branch, err)
+				}
+
+				return
+			}
+
+			if want := `"` + tc.suggestion + `"?`; suggestion != want {
+				t.Errorf("validateBranchName(%q) error = %v, want suggestion %s", tc.branch, err, want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "feature", b: "feature", want: 0},
+		{a: "featrue", b: "feature", want: 2},
+		{a: "feat", b: "feature", want: 3},
+		{a: "", b: "fix", want: 3},
+	}
+
+	for _, tc := range tests {
+		if got := editDistance(tc.a, tc.b); got != tc.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package grove
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/picker"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+)
+
+var ErrAmbiguousBranch = errors.New("ambiguous branch")
+
+// maxListedBranches is the number of candidates listed in the error of an
+// ambiguous branch name.
+const maxListedBranches = 10
+
+// branchMatch is how a name matches a branch, better matches are greater.
+type branchMatch int
+
+const (
+	branchMatchNone branchMatch = iota
+	// The name's characters appear in the branch's slug in order
+	branchMatchSubsequence
+	// The branch's slug contains the name
+	branchMatchSubstring
+	// The name is the ticket ID found in the branch, ignoring case, or just
+	// its number, e.g. fm-3311, 3311 or fm3311 for feature/FM-3311-login
+	branchMatchTicket
+	// The branch's slug starts with the name
+	branchMatchPrefix
+	// The name is the branch, or its slug when the name has no prefix
+	branchMatchExact
+)
+
+// branchCandidate is a branch a name can resolve to.
+type branchCandidate struct {
+	Name string
+	// Local is set for branches that exist locally rather than only on the
+	// remote
+	Local bool
+	// LastUsed is when the branch's worktree was last checked out with grove,
+	// zero if it never was
+	LastUsed time.Time
+}
+
+// resolveBranch resolves the name to one of the local or remote branches.
+// Prefix aliases are expanded and the name may be abbreviated, e.g. `f/login`
+// resolves to `feature/login-page`. Names that don't match any branch, or
+// only loosely, are returned with their aliases expanded and false, so a new
+// branch can be created.
+func (grove *Grove) resolveBranch(ctx context.Context, val string) (string, bool, error) {
+	branches, err := git.ListBranches(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	local, err := git.ListLocalBranches(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	candidates := lo.Map(branches, func(branch string, _ int) branchCandidate {
+		return branchCandidate{Name: branch, Local: slices.Contains(local, branch)}
+	})
+
+	// A new branch that happens to resemble an existing one mustn't prompt
+	return grove.resolve(ctx, val, candidates, false)
+}
+
+// resolveWorkTreeBranch resolves the name to the branch of one of the
+// worktrees, like resolveBranch. As the worktree must exist, names that only
+// loosely match a branch are resolved too, after asking.
+func (grove *Grove) resolveWorkTreeBranch(ctx context.Context, val string) (string, error) {
+	wts, err := git.ListWorkTrees(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := lo.Map(workTreeBranches(wts), func(branch string, _ int) branchCandidate {
+		return branchCandidate{Name: branch, Local: true}
+	})
+
+	branch, _, err := grove.resolve(ctx, val, candidates, true)
+	return branch, err
+}
+
+// resolve resolves the name to one of the candidates, reporting whether any
+// matched. Only a single exact, prefix or ticket match is resolved without
+// asking. Looser matches are ignored unless loose is set, in which case the
+// user is asked since they're likely new names that happen to resemble a
+// branch.
+func (grove *Grove) resolve(ctx context.Context, val string, candidates []branchCandidate, loose bool) (string, bool, error) {
+	used, err := grove.loadRecent()
+	if err != nil {
+		return "", false, err
+	}
+
+	for i := range candidates {
+		candidates[i].LastUsed = used[candidates[i].Name]
+	}
+
+	name := grove.expandPrefixAliases(val)
+
+	matches, match := grove.matchBranches(val, candidates)
+	switch {
+	case len(matches) == 0, match < branchMatchTicket && !loose:
+		return name, false, nil
+	case len(matches) == 1 && match >= branchMatchTicket:
+		slog.DebugContext(ctx, "resolved branch", slog.String("name", val), slog.String("branch", matches[0].Name))
+		return matches[0].Name, true, nil
+	}
+
+	branch, err := chooseBranch(val, name, matches, match < branchMatchTicket)
+	if err != nil {
+		return "", false, err
+	}
+
+	if branch == "" {
+		return name, false, nil
+	}
+
+	return branch, true, nil
+}
+
+// matchBranches returns the candidates that match the name best, most
+// recently used first, and how they match. Exact matches beat prefix
+// matches, which beat ticket matches and so on. Among equal matches, local branches beat
+// remote ones and branches that were checked out with grove beat those that
+// never were.
+func (grove *Grove) matchBranches(val string, candidates []branchCandidate) ([]branchCandidate, branchMatch) {
+	name := grove.expandPrefixAliases(val)
+
+	// The name of a branch can't be ambiguous
+	if c, ok := lo.Find(candidates, func(c branchCandidate) bool { return c.Name == name }); ok {
+		return []branchCandidate{c}, branchMatchExact
+	}
+
+	type rank struct {
+		match branchMatch
+		local bool
+		used  bool
+	}
+
+	compare := func(a, b rank) int {
+		if c := cmp.Compare(a.match, b.match); c != 0 {
+			return c
+		}
+
+		if a.local != b.local {
+			return lo.Ternary(a.local, 1, -1)
+		}
+
+		if a.used != b.used {
+			return lo.Ternary(a.used, 1, -1)
+		}
+
+		return 0
+	}
+
+	var best rank
+	var matches []branchCandidate
+	for _, c := range candidates {
+		r := rank{
+			match: grove.matchBranch(name, c.Name),
+			local: c.Local,
+			used:  !c.LastUsed.IsZero(),
+		}
+
+		if r.match == branchMatchNone {
+			continue
+		}
+
+		switch compare(r, best) {
+		case 1:
+			best = r
+			matches = []branchCandidate{c}
+		case 0:
+			matches = append(matches, c)
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b branchCandidate) int {
+		if c := b.LastUsed.Compare(a.LastUsed); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return matches, best.match
+}
+
+// matchBranch returns how the name, with its aliases expanded, matches the
+// branch. A name with a prefix only matches branches with the same prefix,
+// while a name without one matches branches with any prefix. A ticket ID
+// only matches the branches of the ticket, regardless of case.
+func (grove *Grove) matchBranch(name string, branch string) branchMatch {
+	delimiter := grove.Config.BranchResolver.BranchDelimiter
+	prefix, slug := splitBranch(name, delimiter)
+	branchPrefix, branchSlug := splitBranch(branch, delimiter)
+
+	if slug == "" || (prefix != "" && prefix != branchPrefix) {
+		return branchMatchNone
+	}
+
+	// A ticket ID only refers to its own branches, otherwise a new ticket
+	// would resolve to any branch that happens to contain its characters
+	if grove.isTicketID(slug) {
+		if !strings.EqualFold(slug, grove.ticketID(branch)) {
+			return branchMatchNone
+		}
+
+		return lo.Ternary(strings.EqualFold(slug, branchSlug), branchMatchExact, branchMatchTicket)
+	}
+
+	lowerSlug, lowerBranchSlug := strings.ToLower(slug), strings.ToLower(branchSlug)
+	switch {
+	case lowerSlug == lowerBranchSlug:
+		return branchMatchExact
+	case strings.HasPrefix(lowerBranchSlug, lowerSlug):
+		return branchMatchPrefix
+	case strings.Contains(lowerBranchSlug, lowerSlug):
+		return branchMatchSubstring
+	case grove.isTicketOf(slug, branch):
+		return branchMatchTicket
+	}
+
+	if _, _, ok := picker.Match(slug, branchSlug); ok {
+		return branchMatchSubsequence
+	}
+
+	return branchMatchNone
+}
+
+// isTicketOf reports whether the name is the ticket ID in the branch, with or
+// without the dash, or just the ticket's number.
+func (grove *Grove) isTicketOf(name string, branch string) bool {
+	id := grove.ticketID(branch)
+	if id == "" {
+		return false
+	}
+
+	// Custom ticket patterns may not separate the project from the number
+	i := strings.LastIndex(id, "-")
+	if i < 0 {
+		return strings.EqualFold(name, id)
+	}
+
+	project, number := id[:i], id[i+1:]
+
+	return name == number || strings.EqualFold(name, id) || strings.EqualFold(name, project+number)
+}
+
+// splitBranch splits the branch into its prefix and slug, the part after the
+// last delimiter, e.g. `feature/team` and `login` for `feature/team/login`.
+// The prefix is empty for branches without a delimiter.
+func splitBranch(branch string, delimiter string) (string, string) {
+	i := strings.LastIndex(branch, delimiter)
+	if delimiter == "" || i < 0 {
+		return "", branch
+	}
+
+	return branch[:i], branch[i+len(delimiter):]
+}
+
+// expandPrefixAliases replaces the prefix aliases in the name, e.g. `f/login`
+// becomes `feature/login`.
+func (grove *Grove) expandPrefixAliases(val string) string {
+	br := grove.Config.BranchResolver
+	if br.BranchDelimiter == "" {
+		return val
+	}
+
+	parts := strings.Split(val, br.BranchDelimiter)
+	for i, part := range parts[:len(parts)-1] {
+		if prefix, ok := br.BranchPrefixAliases[config.BranchPrefixAlias(part)]; ok {
+			parts[i] = string(prefix)
+		}
+	}
+
+	return strings.Join(parts, br.BranchDelimiter)
+}
+
+// chooseBranch asks the user which of the branches the name refers to, or
+// returns an error listing them when grove isn't run interactively. When the
+// branches only loosely match, the user may choose none of them, in which
+// case the result is empty and the name is used as it is.
+func chooseBranch(val string, name string, matches []branchCandidate, loose bool) (string, error) {
+	describe := func(c branchCandidate) string {
+		return c.Name + lo.Ternary(c.Local, "", " (remote)")
+	}
+
+	if !util.IsInteractive() {
+		listed := lo.Map(lo.Slice(matches, 0, maxListedBranches), func(c branchCandidate, _ int) string {
+			return describe(c)
+		})
+
+		if len(matches) > maxListedBranches {
+			listed = append(listed, fmt.Sprintf("and %d more", len(matches)-maxListedBranches))
+		}
+
+		if loose {
+			return "", fmt.Errorf("%w %q only partly matches %s, use the full name", ErrAmbiguousBranch, val, strings.Join(listed, ", "))
+		}
+
+		return "", fmt.Errorf("%w %q matches %s, use a longer name", ErrAmbiguousBranch, val, strings.Join(listed, ", "))
+	}
+
+	fmt.Fprintf(os.Stderr, "%q %s:\n", val, lo.Ternary(loose, "partly matches", "matches multiple branches"))
+	if loose {
+		fmt.Fprintf(os.Stderr, "  0) none, use %s\n", name)
+	}
+
+	for i, c := range matches {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, describe(c))
+	}
+
+	first := lo.Ternary(loose, 0, 1)
+	answer, err := util.Prompt(bufio.NewReader(os.Stdin), fmt.Sprintf("Branch [%d-%d]: ", first, len(matches)))
+	if err != nil {
+		return "", err
+	}
+
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < first || n > len(matches) {
+		return "", fmt.Errorf("%w %q, no branch chosen", ErrAmbiguousBranch, val)
+	}
+
+	if n == 0 {
+		return "", nil
+	}
+
+	return matches[n-1].Name, nil
+}
@@ -1,6 +1,8 @@
 package grove
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/jacobdrury/grove/internal/config"
@@ -8,6 +10,7 @@ import (
 
 func TestBranchResolver(t *testing.T) {
 	grove := Grove{
+		GrovePath: t.TempDir(),
 		Config: &config.Config{
 			BranchResolver: config.BranchResolver{
 				BranchPrefixAliases: map[config.BranchPrefixAlias]config.BranchPrefix{"u": "user1"},
@@ -16,34 +19,102 @@ func TestBranchResolver(t *testing.T) {
 		},
 	}
 
-	branches := []string{
-		"user1/fm-331-asdf-asdf",
-		"user1/fm-432-asdf-test",
-		"user1/fm-432-asdf-test-2",
-		"johndoe/asdf-asdf-2",
+	branches := []branchCandidate{
+		{Name: "user1/fm-331-asdf-asdf", Local: true},
+		{Name: "user1/fm-432-asdf-test", Local: true},
+		{Name: "user1/fm-432-asdf-test-2", Local: true},
+		{Name: "johndoe/asdf-asdf-2", Local: true},
+		{Name: "user1/fm-777-remote"},
+		{Name: "user1/fm-777-local", Local: true},
+		{Name: "user1/fm-888-used", Local: true},
+		{Name: "user1/fm-888-unused", Local: true},
+		{Name: "user2/fm-888-used"},
+		{Name: "user1/fm-3310-longer-ticket", Local: true},
+		{Name: "release/ABC-12", Local: true},
+		{Name: "user3/fm-1234-legacy-ordering-in", Local: true},
+	}
+
+	err := grove.touchRecent("user1/fm-888-used")
+	if err != nil {
+		t.Fatal(err)
 	}
 
 	tests := []struct {
-		in  string
-		out string
+		in       string
+		out      string
+		notFound bool
+		// loose resolves loose matches too, like worktree branches are
+		loose bool
+		err   error
 	}{
-		{in: "example", out: "example"},
+		{in: "example", out: "example", notFound: true},
 		{in: "u/fm-331", out: "user1/fm-331-asdf-asdf"},
-		{in: "u/fm-432", out: "user1/fm-432-asdf-test"},
+		{in: "u/fm-432", err: ErrAmbiguousBranch},
 		{in: "u/fm-432-asdf-test", out: "user1/fm-432-asdf-test"},
 		{in: "u/fm-432-asdf-test-2", out: "user1/fm-432-asdf-test-2"},
-		{in: "u/fm-554-asdfasdf", out: "user1/fm-554-asdfasdf"},
-		{in: "n/fm-432", out: "n/fm-432"},
-		{in: "john/asdf-asdf", out: "john/asdf-asdf"},
+		{in: "u/fm-554-asdfasdf", out: "user1/fm-554-asdfasdf", notFound: true},
+		{in: "n/fm-432", out: "n/fm-432", notFound: true},
+		{in: "john/asdf-asdf", out: "john/asdf-asdf", notFound: true},
+		{in: "asdf-asdf-2", out: "johndoe/asdf-asdf-2"},
+		{in: "u/FM-331", out: "user1/fm-331-asdf-asdf"},
+		{in: "fm331", out: "user1/fm-331-asdf-asdf"},
+		{in: "u/fm-777", out: "user1/fm-777-local"},
+		{in: "u/fm-888", out: "user1/fm-888-used"},
+		{in: "fm-888", out: "user1/fm-888-used"},
+		{in: "asdf", out: "johndoe/asdf-asdf-2"},
+		{in: "test", out: "test", notFound: true},
+		{in: "test", loose: true, err: ErrAmbiguousBranch},
+		{in: "FM-331", out: "user1/fm-331-asdf-asdf"},
+		{in: "fm-3310", out: "user1/fm-3310-longer-ticket"},
+		{in: "FM-33", out: "FM-33", notFound: true},
+		{in: "abc-12", out: "release/ABC-12"},
+		{in: "FM-23", out: "FM-23", notFound: true},
+		{in: "fm-124", out: "fm-124", notFound: true},
+		{in: "u/test-2", out: "user1/test-2", notFound: true},
+		{in: "u/f331a", out: "user1/f331a", notFound: true},
+		{in: "user3/login", out: "user3/login", notFound: true},
+		{in: "ordering", out: "ordering", notFound: true},
+		{in: "user3/login", loose: true, err: ErrAmbiguousBranch},
+		{in: "user3/flog", loose: true, err: ErrAmbiguousBranch},
+		{in: "ordering", loose: true, err: ErrAmbiguousBranch},
+		{in: "user3/fm-1234-leg", out: "user3/fm-1234-legacy-ordering-in"},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.in, func(t *testing.T) {
-			t.Parallel()
+			resolved, found, err := grove.resolve(context.Background(), tc.in, append([]branchCandidate(nil), branches...), tc.loose)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("resolve(%q) error = %v, want %v", tc.in, err, tc.err)
+			}
 
-			if resolved := grove.resolveBranch(tc.in, branches); resolved != tc.out {
-				t.Errorf("'%v' -> '%v' != '%v'", tc.in, resolved, tc.out)
+			if resolved != tc.out {
+				t.Errorf("resolve(%q) = %q, want %q", tc.in, resolved, tc.out)
+			}
+
+			if tc.err == nil && found == tc.notFound {
+				t.Errorf("resolve(%q) found = %v, want %v", tc.in, found, !tc.notFound)
 			}
 		})
 	}
 }
+
+func TestIsTicketOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch string
+		want   bool
+	}{
+		{name: "3311", branch: "feature/FM-3311-login", want: true},
+		{name: "fm3311", branch: "feature/FM-3311-login", want: true},
+		{name: "fm-3311", branch: "feature/FM-3311-login", want: true},
+		{name: "331", branch: "feature/FM-3311-login", want: false},
+		{name: "3311", branch: "feature/login", want: false},
+	}
+
+	grove := Grove{Config: config.DefaultConfig()}
+	for _, tc := range tests {
+		if got := grove.isTicketOf(tc.name, tc.branch); got != tc.want {
+			t.Errorf("isTicketOf(%q, %q) = %v, want %v", tc.name, tc.branch, got, tc.want)
+		}
+	}
+}
@@ -6,27 +6,54 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"strings"
+	"path/filepath"
 
 	"github.com/jacobdrury/grove/internal/config"
 	"github.com/jacobdrury/grove/internal/git"
 	"github.com/jacobdrury/grove/internal/util"
-	"github.com/otiai10/copy"
-	"github.com/samber/lo"
 )
 
 type CheckoutArgs struct {
 	Branch string // Supports aliases j/fm-3311
+	From   string // Commit, tag or branch to create new branches from, defaults to the base branch
+	Title  string // Title of the ticket a new branch is named after, fetched with the title provider when empty
+	New    bool   // Use the name as it is rather than resolving it against existing branches
 }
 
 func (grove *Grove) Checkout(ctx context.Context, arg CheckoutArgs) (*git.WorkTree, error) {
 	return util.InDirectory(grove.RepositoryPath, func() (*git.WorkTree, error) {
-		branches, err := git.ListBranches(ctx)
-		if err != nil {
-			return nil, err
+		var err error
+		branch, found := arg.Branch, false
+		if !arg.New {
+			branch, found, err = grove.resolveBranch(ctx, arg.Branch)
+			if errors.Is(err, ErrAmbiguousBranch) {
+				return nil, fmt.Errorf("%w, or pass --new to create a new branch", err)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !found {
+			branch, err = grove.newBranchName(ctx, arg.Branch, arg.Title)
+			if err != nil {
+				return nil, err
+			}
+
+			// Validate before anything runs for the branch, e.g. the
+			// before-checkout hooks. A branch that exists on the remote but
+			// wasn't fetched yet isn't new, whatever its name.
+			if !git.BranchExists(ctx, branch) {
+				err = grove.validateBranchName(ctx, branch)
+				if err != nil {
+					return nil, err
+				}
+			}
+		} else if arg.Title != "" {
+			slog.WarnContext(ctx, "ignoring title, the branch already exists", slog.String("branch", branch))
 		}
 
-		branch := grove.resolveBranch(arg.Branch, branches)
 		util.LogInfo(ctx, "checking out", slog.String("branch", branch))
 
 		wt, err := git.FindWorkTree(ctx, branch)
@@ -34,9 +61,25 @@ func (grove *Grove) Checkout(ctx context.Context, arg CheckoutArgs) (*git.WorkTr
 			return nil, err
 		}
 
+		path := filepath.Join(grove.WorkTreesPath, branch)
 		if wt != nil {
+			path = wt.Path
+		}
+
+		data, err := grove.templateData(ctx, branch, path, wt == nil)
+		if err != nil {
+			return nil, err
+		}
+
+		err = grove.executeHooks(ctx, config.HookEventBeforeCheckout, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if wt != nil {
+			warnIgnoredStartPoint(ctx, arg.From, branch)
 			util.LogInfo(ctx, "worktree already exists, switching to it")
-			return checkoutWorkTree(ctx, grove, wt)
+			return checkoutWorkTree(ctx, grove, wt, data)
 		}
 
 		err = git.Fetch(ctx, "-p")
@@ -46,6 +89,7 @@ func (grove *Grove) Checkout(ctx context.Context, arg CheckoutArgs) (*git.WorkTr
 
 		// 2. If branch exists on remote, add a new worktree for
 		if git.BranchExists(ctx, branch) {
+			warnIgnoredStartPoint(ctx, arg.From, branch)
 			util.LogInfo(ctx, "branch exists on remote, creating new worktree from branch")
 
 			wt, err = git.CreateWorkTreeFromBranch(ctx, grove.Config.WorkTreesDirectory, branch)
@@ -53,102 +97,107 @@ func (grove *Grove) Checkout(ctx context.Context, arg CheckoutArgs) (*git.WorkTr
 				return nil, err
 			}
 
-			return checkoutWorkTree(ctx, grove, wt)
+			return checkoutWorkTree(ctx, grove, wt, data)
 		}
 
-		mainWt, err := git.FindWorkTree(ctx, "main")
-		if err != nil {
-			return nil, fmt.Errorf("error finding main worktree: %v", err)
+		startPoint := arg.From
+		if startPoint == "" {
+			startPoint, err = grove.baseRef(ctx, branch)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		// Update main worktree
-		err = util.InDirectoryNoResult(mainWt.Path, func() error {
-			util.LogInfo(ctx, "pulling main")
-
-			return git.Pull(ctx)
-		})
+		util.LogInfo(ctx, "creating new worktree", slog.String("branch", branch), slog.String("from", startPoint))
+		wt, err = git.CreateWorkTreeFromNewBranch(ctx, grove.Config.WorkTreesDirectory, branch, startPoint)
 		if err != nil {
 			return nil, err
 		}
 
-		util.LogInfo(ctx, "creating new worktree based on main", slog.String("branch", branch))
-		wt, err = git.CreateWorkTreeFromNewBranch(ctx, grove.Config.WorkTreesDirectory, branch)
+		// New branches don't track anything until they're pushed, the first
+		// push sets up tracking
+		err = git.EnableAutoSetupRemote(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		return checkoutWorkTree(ctx, grove, wt)
+		return checkoutWorkTree(ctx, grove, wt, data)
 	})
 }
 
-func (grove *Grove) seedWorkTree(ctx context.Context, wt *git.WorkTree) error {
-	slog.DebugContext(ctx, "seeding worktree", slog.String("workTreePath", wt.Path), slog.String("seedDirectory", grove.SeedPath))
-
-	return copy.Copy(grove.SeedPath, wt.Path)
+// warnIgnoredStartPoint warns that the start point passed with --from isn't
+// used, as the branch already exists.
+func warnIgnoredStartPoint(ctx context.Context, from string, branch string) {
+	if from != "" {
+		slog.WarnContext(ctx, "ignoring start point, the branch already exists", slog.String("branch", branch), slog.String("from", from))
+	}
 }
 
-func (grove *Grove) resolveBranch(val string, branches []string) string {
-	br := grove.Config.BranchResolver
+// checkoutWorkTree switches to the worktree and runs the checkout hooks.
+// data.IsNew indicates whether the worktree was created as part of this
+// checkout.
+func checkoutWorkTree(ctx context.Context, grove *Grove, wt *git.WorkTree, data *TemplateData) (*git.WorkTree, error) {
+	slog.DebugContext(ctx, "checking out worktree", slog.String("path", wt.Path), slog.Bool("isNew", data.IsNew))
 
-	originalParts := strings.Split(val, br.BranchDelimiter)
-	parts := originalParts
+	data.Path = wt.Path
 
-	for i, part := range parts {
-		// Expand aliases
-		if i != len(parts)-1 {
-			if alias, ok := br.BranchPrefixAliases[config.BranchPrefixAlias(part)]; ok {
-				parts[i] = string(alias)
-			}
+	err := os.Chdir(wt.Path)
+	if err != nil {
+		return nil, err
+	}
 
-			continue
-		}
+	// The port is only saved now that the worktree exists, so a failed
+	// checkout doesn't keep it allocated
+	data.Port, err = grove.allocatePort(ctx, data.Branch)
+	if err != nil {
+		return nil, err
+	}
 
-		// Resolve by exact match
-		if branch, ok := lo.Find(branches, func(branch string) bool {
-			return branch == strings.Join(parts, br.BranchDelimiter)
-		}); ok {
-			return branch
-		}
+	// New branches don't track anything until they're pushed, so there's
+	// nothing to pull. We don't care if it fails, just want to try and update
+	// the branch.
+	if upstream, err := git.GetUpstream(ctx, data.Branch); err == nil && upstream != "" {
+		_ = git.Pull(ctx)
+	}
 
-		// Resolve slug by prefix match
-		for _, branch := range branches {
-			branchParts := strings.Split(branch, br.BranchDelimiter)
-			slug := branchParts[len(branchParts)-1]
-			prefix := strings.Join(branchParts[:len(branchParts)-1], br.BranchDelimiter)
-			resolvedPrefix := strings.Join(parts[:len(parts)-1], br.BranchDelimiter)
+	// Copy seed files first, explicit seed files take precedence over the
+	// ones from the main worktree, which skip existing files
+	err = grove.seedWorkTree(ctx, wt, data)
+	if err != nil {
+		return nil, err
+	}
 
-			if strings.HasPrefix(slug, part) && prefix == resolvedPrefix {
-				parts[i] = slug
-				break
-			}
+	if data.IsNew {
+		err = grove.seedFromMainWorkTree(ctx, wt)
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	return strings.Join(parts, br.BranchDelimiter)
-}
+		err = grove.cloneFromMainWorkTree(ctx, wt)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-func checkoutWorkTree(ctx context.Context, grove *Grove, wt *git.WorkTree) (*git.WorkTree, error) {
-	slog.DebugContext(ctx, "checking out worktree", slog.String("path", wt.Path))
+	event := config.HookEventAfterSwitch
+	if data.IsNew {
+		event = config.HookEventAfterCreate
+	}
 
-	err := os.Chdir(wt.Path)
+	err = grove.executeHooks(ctx, event, data)
 	if err != nil {
 		return nil, err
 	}
 
-	// We don't care if it fails, just want to try and update the branch
-	_ = git.Pull(ctx)
-
-	// Copy seed files
-	err = grove.seedWorkTree(ctx, wt)
+	err = grove.executeHooks(ctx, config.HookEventAfterCheckout, data)
 	if err != nil {
 		return nil, err
 	}
 
-	if !config.NoHooks(ctx) {
-		err = grove.executeAfterCheckoutHooks(ctx)
-		if err != nil {
-			return nil, err
-		}
+	// Only used to rank worktrees, so it's not worth failing the checkout
+	err = grove.touchRecent(data.Branch)
+	if err != nil {
+		slog.WarnContext(ctx, "error recording checkout", slog.String("branch", data.Branch), slog.String("error", err.Error()))
 	}
 
 	util.LogInfo(ctx, "checked out worktree", slog.String("path", wt.Path))
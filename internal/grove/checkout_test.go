@@ -0,0 +1,213 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestCheckoutNewBranch(t *testing.T) {
+	grove, _ := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+	repo := grove.RepositoryPath
+
+	// Leave the local base branch behind the remote one
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "second")
+	runGit(t, repo, "push", "-q", "origin", "HEAD:main")
+	runGit(t, repo, "reset", "-q", "--hard", "HEAD~1")
+
+	ctx := config.ContextWithNoHooks(context.Background())
+
+	wt, err := grove.Checkout(ctx, CheckoutArgs{Branch: "feature/new", New: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wt.Branch != "feature/new" {
+		t.Errorf("Checkout() branch = %q, want feature/new", wt.Branch)
+	}
+
+	if head, remote := runGit(t, repo, "rev-parse", "feature/new"), runGit(t, repo, "rev-parse", "origin/main"); head != remote {
+		t.Errorf("feature/new = %s, want it created from origin/main %s", head, remote)
+	}
+
+	// The branch must not track anything until it's pushed, otherwise it
+	// looks like its upstream is gone
+	cmd := exec.Command("git", "config", "--get", "branch.feature/new.merge")
+	cmd.Dir = repo
+	if out, err := cmd.Output(); err == nil {
+		t.Errorf("feature/new tracks %s, want no upstream", out)
+	}
+
+	// The first push sets up tracking
+	if got := runGit(t, repo, "config", "--get", "push.autoSetupRemote"); got != "true" {
+		t.Errorf("push.autoSetupRemote = %q, want true", got)
+	}
+
+	runGit(t, wt.Path, "push", "-q")
+	if upstream := runGit(t, repo, "for-each-ref", "--format=%(upstream:short)", "refs/heads/feature/new"); upstream != "origin/feature/new" {
+		t.Errorf("feature/new tracks %q after pushing, want origin/feature/new", upstream)
+	}
+
+	// An explicit setting is left alone
+	runGit(t, repo, "config", "push.autoSetupRemote", "false")
+
+	_, err = grove.Checkout(ctx, CheckoutArgs{Branch: "feature/other", New: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := runGit(t, repo, "config", "--get", "push.autoSetupRemote"); got != "false" {
+		t.Errorf("push.autoSetupRemote = %q, want the configured false", got)
+	}
+}
+
+func TestCheckoutRemoteBranch(t *testing.T) {
+	grove, wtPath := newTestRepo(t)
+	repo := grove.RepositoryPath
+
+	// Only keep feature/x on the remote
+	runGit(t, repo, "worktree", "remove", wtPath)
+	runGit(t, repo, "branch", "-q", "-D", "feature/x")
+
+	ctx := config.ContextWithNoHooks(context.Background())
+
+	// The start point is ignored for existing branches
+	wt, err := grove.Checkout(ctx, CheckoutArgs{Branch: "feature/x", From: "does-not-exist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wt.Branch != "feature/x" {
+		t.Errorf("Checkout() branch = %q, want feature/x", wt.Branch)
+	}
+
+	if upstream := runGit(t, repo, "for-each-ref", "--format=%(upstream:short)", "refs/heads/feature/x"); upstream != "origin/feature/x" {
+		t.Errorf("feature/x tracks %q, want origin/feature/x", upstream)
+	}
+}
+
+func TestCheckoutPort(t *testing.T) {
+	grove, _ := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+	ctx := config.ContextWithNoHooks(context.Background())
+
+	// A failed checkout must not keep the port allocated
+	_, err := grove.Checkout(ctx, CheckoutArgs{Branch: "feature/new", From: "does-not-exist", New: true})
+	if err == nil {
+		t.Fatal("Checkout() from a missing start point succeeded")
+	}
+
+	assertPorts(t, grove, ports{})
+
+	_, err = grove.Checkout(ctx, CheckoutArgs{Branch: "feature/new", New: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPorts(t, grove, ports{"feature/new": 3000})
+}
+
+func TestCheckoutSeedPrecedence(t *testing.T) {
+	grove, _ := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+	grove.Config.Seed.FromMain = []string{".env"}
+	grove.Config.Seed.Files = []config.SeedFile{{Path: ".env", Mode: config.SeedModeCopyOnce}}
+
+	// The main worktree's .env is untracked, so it would be copied too
+	err := os.WriteFile(filepath.Join(grove.RepositoryPath, ".env"), []byte("main"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(grove.SeedPath, ".env"), []byte("seed"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wt, err := grove.Checkout(config.ContextWithNoHooks(context.Background()), CheckoutArgs{Branch: "feature/new", New: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(wt.Path, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "seed" {
+		t.Errorf(".env = %q, want the seed file to take precedence", content)
+	}
+}
+
+func TestCheckoutLowercaseTicket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the title provider is run with /bin/sh")
+	}
+
+	grove, _ := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+	grove.Config.Hooks.Shell = "/bin/sh"
+	grove.Config.BranchResolver.Tickets.TitleProvider = "exit 1"
+
+	// node-18 matches the ticket pattern ignoring case, but isn't a ticket
+	wt, err := grove.Checkout(config.ContextWithNoHooks(context.Background()), CheckoutArgs{Branch: "chore/node-18"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wt.Branch != "chore/node-18" {
+		t.Errorf("Checkout() branch = %q, want chore/node-18", wt.Branch)
+	}
+}
+
+func TestCheckoutInvalidBranchName(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	grove, _ := newTestRepo(t)
+	grove.Config.BranchPolicy.Prefixes = []string{"feature"}
+
+	out := filepath.Join(t.TempDir(), "out")
+	grove.Config.Hooks = config.Hooks{
+		Shell:          "/bin/sh",
+		BeforeCheckout: []config.Hook{{Name: "record", Run: "touch " + out}},
+	}
+
+	_, err := grove.Checkout(context.Background(), CheckoutArgs{Branch: "wip/x", New: true})
+	if !errors.Is(err, ErrInvalidBranchName) {
+		t.Fatalf("Checkout() error = %v, want %v", err, ErrInvalidBranchName)
+	}
+
+	// The name is rejected before anything runs for the branch
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("before-checkout hooks ran for an invalid branch name")
+	}
+}
+
+func TestCheckoutUnfetchedRemoteBranch(t *testing.T) {
+	grove, _ := newTestRepo(t)
+	grove.Config.BranchPolicy.Prefixes = []string{"feature"}
+	repo := grove.RepositoryPath
+
+	// The branch was pushed by someone else and hasn't been fetched yet
+	runGit(t, repo, "push", "-q", "origin", "HEAD:refs/heads/JIRA-12_fix")
+	runGit(t, repo, "update-ref", "-d", "refs/remotes/origin/JIRA-12_fix")
+
+	// Existing branches are checked out whatever their name
+	wt, err := grove.Checkout(config.ContextWithNoHooks(context.Background()), CheckoutArgs{Branch: "JIRA-12_fix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if wt.Branch != "JIRA-12_fix" {
+		t.Errorf("Checkout() branch = %q, want JIRA-12_fix", wt.Branch)
+	}
+}
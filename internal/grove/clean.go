@@ -0,0 +1,270 @@
+package grove
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+// CleanReason describes why a worktree is no longer needed.
+type CleanReason string
+
+const (
+	// CleanReasonMerged is used for branches merged into their base branch
+	CleanReasonMerged CleanReason = "merged"
+	// CleanReasonSquashMerged is used for branches whose changes were
+	// squashed or rebased onto their base branch
+	CleanReasonSquashMerged CleanReason = "squash-merged"
+	// CleanReasonUpstreamGone is used for branches whose upstream was
+	// deleted on the remote
+	CleanReasonUpstreamGone CleanReason = "upstream gone"
+)
+
+type CleanArgs struct {
+	Fetch bool // Fetch and prune remote-tracking branches first, so deleted upstreams are detected
+}
+
+// CleanCandidate is a worktree that is no longer needed.
+type CleanCandidate struct {
+	Path   string
+	Branch string
+	Reason CleanReason
+	// Base is the ref the branch was merged into, empty when the upstream is
+	// gone but the branch wasn't merged
+	Base string
+	// Keep explains why the worktree won't be removed, e.g. because it has
+	// uncommitted changes. It's empty when the worktree can be removed.
+	Keep string
+}
+
+// PlanClean finds the worktrees whose branches were merged, squash-merged or
+// deleted on the remote. Nothing is removed, the candidates are removed with
+// Remove.
+func (grove *Grove) PlanClean(ctx context.Context, arg CleanArgs) ([]CleanCandidate, error) {
+	ctx = git.ContextWithDir(ctx, grove.RepositoryPath)
+
+	if arg.Fetch {
+		err := git.Fetch(ctx, "-p")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wts, err := git.ListWorkTrees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upstreams, err := git.ListUpstreams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []CleanCandidate
+	for i, wt := range wts {
+		// The main worktree can't be removed and a prunable worktree no
+		// longer exists, which `grove prune` takes care of
+		if i == 0 || wt.Bare || wt.Detached || wt.Prunable {
+			continue
+		}
+
+		candidate, err := grove.cleanCandidate(ctx, wt, upstreams[wt.Branch])
+		if err != nil {
+			return nil, fmt.Errorf("error checking %s: %w", wt.Branch, err)
+		}
+
+		if candidate == nil {
+			continue
+		}
+
+		if candidate.Keep == "" && isWithin(cwd, wt.Path) {
+			candidate.Keep = "current directory"
+		}
+
+		candidates = append(candidates, *candidate)
+	}
+
+	return candidates, nil
+}
+
+// cleanCandidate returns the candidate for the worktree, or nil if its branch
+// is still needed or hasn't been worked on.
+func (grove *Grove) cleanCandidate(ctx context.Context, wt git.WorkTree, upstream git.Upstream) (*CleanCandidate, error) {
+	slog.DebugContext(ctx, "checking whether worktree can be cleaned", slog.String("branch", wt.Branch))
+
+	// A branch that was just created is merged into the base branch it was
+	// created from. Branches checked out from the remote are checked like
+	// any other, they may well have been merged before being checked out.
+	if upstream.Name == "" {
+		untouched, err := isUntouched(ctx, wt)
+		if err != nil {
+			return nil, err
+		}
+
+		if untouched {
+			slog.DebugContext(ctx, "branch has not moved since it was created", slog.String("branch", wt.Branch))
+			return nil, nil
+		}
+	}
+
+	base, err := grove.baseRef(ctx, wt.Branch)
+	if err != nil {
+		return nil, err
+	}
+
+	candidate := &CleanCandidate{
+		Path:   wt.Path,
+		Branch: wt.Branch,
+	}
+
+	// The base branch is never merged into itself
+	if base != wt.Branch && base != defaultRemote+"/"+wt.Branch {
+		candidate.Reason, err = mergeReason(ctx, wt.Head, base)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if candidate.Reason != "" {
+		candidate.Base = base
+	} else if upstream.Gone {
+		candidate.Reason = CleanReasonUpstreamGone
+	} else {
+		return nil, nil
+	}
+
+	candidate.Keep, err = keepReason(ctx, wt, candidate.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return candidate, nil
+}
+
+// mergeReason returns how head was merged into base, or an empty reason if
+// it wasn't.
+func mergeReason(ctx context.Context, head string, base string) (CleanReason, error) {
+	merged, err := git.IsAncestor(ctx, head, base)
+	if err != nil {
+		return "", err
+	}
+
+	if merged {
+		return CleanReasonMerged, nil
+	}
+
+	mergeBase, err := git.GetMergeBase(ctx, head, base)
+	if err != nil {
+		return "", err
+	}
+
+	tree, err := git.GetTree(ctx, head)
+	if err != nil {
+		return "", err
+	}
+
+	mergeBaseTree, err := git.GetTree(ctx, mergeBase)
+	if err != nil {
+		return "", err
+	}
+
+	// Without any changes there is nothing to compare
+	if tree == mergeBaseTree {
+		return "", nil
+	}
+
+	// Nothing has been merged into the base branch since the branch was
+	// squash-merged
+	baseTree, err := git.GetTree(ctx, base)
+	if err != nil {
+		return "", err
+	}
+
+	if tree == baseTree {
+		return CleanReasonSquashMerged, nil
+	}
+
+	// Rebased commits have the same patch-ids as the original ones
+	unapplied, err := git.CountUnappliedCommits(ctx, base, head)
+	if err != nil {
+		return "", err
+	}
+
+	if unapplied == 0 {
+		return CleanReasonSquashMerged, nil
+	}
+
+	// A squash merge has the same patch-id as the branch's changes squashed
+	// into a single commit
+	squashed, err := git.GetDiffPatchID(ctx, mergeBase, head)
+	if err != nil {
+		return "", err
+	}
+
+	applied, err := git.ListPatchIDs(ctx, mergeBase, base)
+	if err != nil {
+		return "", err
+	}
+
+	if slices.Contains(applied, squashed) {
+		return CleanReasonSquashMerged, nil
+	}
+
+	return "", nil
+}
+
+// keepReason explains why the worktree shouldn't be removed even though its
+// branch was merged or its upstream is gone, or returns an empty string if it
+// can be removed.
+func keepReason(ctx context.Context, wt git.WorkTree, reason CleanReason) (string, error) {
+	if wt.Locked {
+		return "locked", nil
+	}
+
+	wtCtx := git.ContextWithDir(ctx, wt.Path)
+
+	changed, err := git.ListChangedFiles(wtCtx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(changed) > 0 {
+		return "uncommitted changes", nil
+	}
+
+	// The upstream may have been deleted without being merged, so keep the
+	// commits that would be lost
+	if reason == CleanReasonUpstreamGone {
+		unpushed, err := git.CountUnpushedCommits(wtCtx)
+		if err != nil {
+			return "", err
+		}
+
+		if unpushed > 0 {
+			return "unpushed commits", nil
+		}
+	}
+
+	return "", nil
+}
+
+// isWithin reports whether path is dir or inside of it.
+func isWithin(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,144 @@
+package grove
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+func TestMergeReason(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	ctx := git.ContextWithDir(context.Background(), dir)
+
+	run := func(args ...string) {
+		t.Helper()
+
+		_, err := git.Execute(ctx, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit := func(file string) {
+		t.Helper()
+
+		err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		run("add", file)
+		run("commit", "-q", "-m", file)
+	}
+
+	run("init", "-q", "-b", "main")
+	run("config", "user.name", "test")
+	run("config", "user.email", "test@localhost")
+	commit("base")
+
+	branch := func(name string, files ...string) {
+		t.Helper()
+
+		run("checkout", "-q", "-b", name, "main")
+		for _, file := range files {
+			commit(file)
+		}
+	}
+
+	branch("merged", "merged")
+	branch("squashed", "squashed-1", "squashed-2")
+	branch("rebased", "rebased-1", "rebased-2")
+	branch("unmerged", "unmerged")
+
+	run("checkout", "-q", "main")
+	run("merge", "-q", "--no-ff", "--no-edit", "merged")
+	run("merge", "-q", "--squash", "squashed")
+	run("commit", "-q", "-m", "squashed")
+	run("cherry-pick", "rebased~1", "rebased")
+	commit("after")
+
+	tests := []struct {
+		branch string
+		want   CleanReason
+	}{
+		{branch: "merged", want: CleanReasonMerged},
+		{branch: "squashed", want: CleanReasonSquashMerged},
+		{branch: "rebased", want: CleanReasonSquashMerged},
+		{branch: "unmerged", want: ""},
+	}
+
+	objects := runGit(t, dir, "count-objects")
+
+	for _, tc := range tests {
+		t.Run(tc.branch, func(t *testing.T) {
+			got, err := mergeReason(ctx, tc.branch, "main")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tc.want {
+				t.Errorf("mergeReason(%q) = %q, want %q", tc.branch, got, tc.want)
+			}
+		})
+	}
+
+	// Checking must not write to the repository
+	if got := runGit(t, dir, "count-objects"); got != objects {
+		t.Errorf("objects = %q, want %q", got, objects)
+	}
+}
+
+func TestPlanClean(t *testing.T) {
+	grove, _ := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+	ctx := context.Background()
+
+	// The branch was merged and deleted on the remote
+	checkoutMergedRemoteBranch(t, grove, "feature/remote")
+	runGit(t, grove.RepositoryPath, "push", "-q", "origin", "--delete", "feature/remote")
+
+	candidates, err := grove.PlanClean(ctx, CleanArgs{Fetch: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// feature/x was created locally and hasn't been worked on
+	want := []CleanCandidate{{
+		Path:   filepath.Join(grove.WorkTreesPath, "feature", "remote"),
+		Branch: "feature/remote",
+		Reason: CleanReasonMerged,
+		Base:   "origin/main",
+	}}
+	if !slices.Equal(candidates, want) {
+		t.Errorf("PlanClean() = %+v, want %+v", candidates, want)
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	dir := filepath.Join("repo", "worktrees", "feature")
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: dir, want: true},
+		{path: filepath.Join(dir, "src"), want: true},
+		{path: filepath.Join("repo", "worktrees", "feature-2"), want: false},
+		{path: "repo", want: false},
+	}
+
+	for _, tc := range tests {
+		if got := isWithin(tc.path, dir); got != tc.want {
+			t.Errorf("isWithin(%q, %q) = %v, want %v", tc.path, dir, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package grove
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+)
+
+// CompleteBranch returns the branches, worktrees and prefix aliases that
+// complete val.
+func (grove *Grove) CompleteBranch(ctx context.Context, val string) ([]string, error) {
+	return util.InDirectory(grove.RepositoryPath, func() ([]string, error) {
+		branches, err := git.ListBranches(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		wts, err := git.ListWorkTrees(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		branches = append(branches, workTreeBranches(wts)...)
+
+		return grove.completeBranch(val, branches, true), nil
+	})
+}
+
+// CompleteWorkTree returns the branches of existing worktrees that complete
+// val.
+func (grove *Grove) CompleteWorkTree(ctx context.Context, val string) ([]string, error) {
+	return util.InDirectory(grove.RepositoryPath, func() ([]string, error) {
+		wts, err := git.ListWorkTrees(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return grove.completeBranch(val, workTreeBranches(wts), false), nil
+	})
+}
+
+// completeBranch returns the branches that complete val. Prefix aliases typed
+// in val are preserved in the completions so that shells, which filter
+// completions by what has been typed, don't discard them, e.g. `f/` is
+// completed to `f/some-feature` for the branch `feature/some-feature`.
+func (grove *Grove) completeBranch(val string, branches []string, includeAliases bool) []string {
+	br := grove.Config.BranchResolver
+
+	parts := strings.Split(val, br.BranchDelimiter)
+	typedPrefix := parts[:len(parts)-1]
+	expandedPrefix := lo.Map(typedPrefix, func(part string, _ int) string {
+		if alias, ok := br.BranchPrefixAliases[config.BranchPrefixAlias(part)]; ok {
+			return string(alias)
+		}
+
+		return part
+	})
+
+	var completions []string
+	if len(typedPrefix) == 0 {
+		completions = lo.Filter(branches, func(branch string, _ int) bool {
+			return strings.HasPrefix(branch, val)
+		})
+	} else {
+		expanded := strings.Join(expandedPrefix, br.BranchDelimiter) + br.BranchDelimiter
+		typed := strings.Join(typedPrefix, br.BranchDelimiter) + br.BranchDelimiter
+		last := parts[len(parts)-1]
+
+		for _, branch := range branches {
+			rest, ok := strings.CutPrefix(branch, expanded)
+			if ok && strings.HasPrefix(rest, last) {
+				completions = append(completions, typed+rest)
+			}
+		}
+	}
+
+	if includeAliases && len(typedPrefix) == 0 {
+		for alias := range br.BranchPrefixAliases {
+			if strings.HasPrefix(string(alias), val) {
+				completions = append(completions, string(alias)+br.BranchDelimiter)
+			}
+		}
+	}
+
+	completions = lo.Uniq(completions)
+	slices.Sort(completions)
+
+	return completions
+}
+
+func workTreeBranches(wts []git.WorkTree) []string {
+	return lo.FilterMap(wts, func(wt git.WorkTree, _ int) (string, bool) {
+		return wt.Branch, wt.Branch != ""
+	})
+}
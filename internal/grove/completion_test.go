@@ -0,0 +1,51 @@
+package grove
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestCompleteBranch(t *testing.T) {
+	grove := Grove{
+		Config: &config.Config{
+			BranchResolver: config.BranchResolver{
+				BranchPrefixAliases: map[config.BranchPrefixAlias]config.BranchPrefix{"f": "feature", "u": "user1"},
+				BranchDelimiter:     "/",
+			},
+		},
+	}
+
+	branches := []string{
+		"main",
+		"feature/login",
+		"feature/logout",
+		"feature/signup",
+		"fix/typo",
+		"user1/fm-331-asdf",
+	}
+
+	tests := []struct {
+		in  string
+		out []string
+	}{
+		{in: "", out: []string{"f/", "feature/login", "feature/logout", "feature/signup", "fix/typo", "main", "u/", "user1/fm-331-asdf"}},
+		{in: "f", out: []string{"f/", "feature/login", "feature/logout", "feature/signup", "fix/typo"}},
+		{in: "f/", out: []string{"f/login", "f/logout", "f/signup"}},
+		{in: "f/log", out: []string{"f/login", "f/logout"}},
+		{in: "feature/s", out: []string{"feature/signup"}},
+		{in: "u/fm", out: []string{"u/fm-331-asdf"}},
+		{in: "x/", out: []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			t.Parallel()
+
+			if completions := grove.completeBranch(tc.in, branches, true); !reflect.DeepEqual(completions, tc.out) {
+				t.Errorf("'%v' -> %v != %v", tc.in, completions, tc.out)
+			}
+		})
+	}
+}
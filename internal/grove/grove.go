@@ -26,6 +26,10 @@ const (
 	GroveDirectoryName string = ".grove"
 	SeedDirectoryName  string = "seed"
 	ConfigFileName     string = "config.yaml"
+	PortsFileName      string = "ports.yaml"
+	SeededFileName     string = "seeded.yaml"
+	RecentFileName     string = "recent.yaml"
+	HooksDirectoryName string = "hooks"
 )
 
 type Grove struct {
@@ -88,10 +92,13 @@ func New(ctx context.Context) (*Grove, error) {
 	wtDir := filepath.Join(wd, GroveDirectoryName)
 	seedDir := filepath.Join(wtDir, SeedDirectoryName)
 
+	cfg := config.DefaultConfig()
 	grove := &Grove{
-		GrovePath: wtDir,
-		Config:    config.DefaultConfig(),
-		SeedPath:  seedDir,
+		RepositoryPath: wd,
+		GrovePath:      wtDir,
+		Config:         cfg,
+		WorkTreesPath:  resolveWorkTreesPath(wd, cfg),
+		SeedPath:       seedDir,
 	}
 
 	err = grove.persist()
@@ -156,14 +163,27 @@ func load() (*Grove, error) {
 		return nil, fmt.Errorf("invalid config: %v", err)
 	}
 
+	repoPath := filepath.Dir(groveDir)
+
 	return &Grove{
-		RepositoryPath: filepath.Dir(groveDir),
+		RepositoryPath: repoPath,
 		GrovePath:      groveDir,
 		Config:         cfg,
+		WorkTreesPath:  resolveWorkTreesPath(repoPath, cfg),
 		SeedPath:       seedPath,
 	}, nil
 }
 
+// resolveWorkTreesPath resolves the configured worktrees directory relative
+// to the repository root.
+func resolveWorkTreesPath(repoPath string, cfg *config.Config) string {
+	if filepath.IsAbs(cfg.WorkTreesDirectory) {
+		return cfg.WorkTreesDirectory
+	}
+
+	return filepath.Join(repoPath, cfg.WorkTreesDirectory)
+}
+
 func locateGroveDir(startPath string) (string, error) {
 	dir := startPath
 	for {
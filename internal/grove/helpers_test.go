@@ -0,0 +1,95 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+// fakeRunner responds to git commands with canned output, keyed by the
+// space-joined arguments, and records every command it receives.
+type fakeRunner struct {
+	responses map[string]string
+	commands  []string
+}
+
+func (f *fakeRunner) Run(ctx context.Context, args ...string) (*git.Result, error) {
+	key := strings.Join(args, " ")
+	f.commands = append(f.commands, key)
+
+	if out, ok := f.responses[key]; ok {
+		return &git.Result{Stdout: out}, nil
+	}
+
+	return nil, &git.Error{Args: args, ExitCode: 1, Err: errors.New("unexpected command")}
+}
+
+// runGit runs the git command in the directory and returns its output.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// newTestRepo creates a repository cloned from a bare origin, with a
+// worktree of the pushed branch feature/x, and returns the grove and the
+// worktree's path.
+func newTestRepo(t *testing.T) (*Grove, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	origin := filepath.Join(dir, "origin.git")
+	repo := filepath.Join(dir, "repo")
+	wtPath := filepath.Join(repo, "worktrees", "feature", "x")
+
+	runGit(t, dir, "init", "-q", "--bare", "-b", "main", origin)
+	runGit(t, dir, "clone", "-q", origin, repo)
+	runGit(t, repo, "config", "user.name", "test")
+	runGit(t, repo, "config", "user.email", "test@localhost")
+	runGit(t, repo, "commit", "-q", "--allow-empty", "-m", "initial")
+	runGit(t, repo, "push", "-q", "origin", "HEAD:main")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature/x", wtPath)
+	runGit(t, wtPath, "push", "-q", "origin", "feature/x")
+
+	grove := &Grove{
+		Config:         config.DefaultConfig(),
+		RepositoryPath: repo,
+		GrovePath:      t.TempDir(),
+		WorkTreesPath:  filepath.Join(repo, "worktrees"),
+		SeedPath:       t.TempDir(),
+	}
+
+	return grove, wtPath
+}
+
+// checkoutMergedRemoteBranch pushes the branch with a commit, merges it into
+// the remote main branch and checks it out from the remote into a worktree
+// of its own, whose path is returned. The branch is left untouched since it
+// was checked out, like one that was merged before it was checked out.
+func checkoutMergedRemoteBranch(t *testing.T, grove *Grove, branch string) string {
+	t.Helper()
+
+	repo := grove.RepositoryPath
+	wtPath := filepath.Join(grove.WorkTreesPath, filepath.FromSlash(branch))
+
+	runGit(t, repo, "fetch", "-q")
+	head := runGit(t, repo, "commit-tree", "-p", "origin/main", "-m", branch, "origin/main^{tree}")
+	runGit(t, repo, "push", "-q", "origin", head+":refs/heads/"+branch, head+":refs/heads/main")
+	runGit(t, repo, "fetch", "-q")
+	runGit(t, repo, "worktree", "add", "-q", "--track", "-b", branch, wtPath, "origin/"+branch)
+
+	return wtPath
+}
@@ -0,0 +1,324 @@
+package grove
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+const (
+	HookRunRunning   = "running"
+	HookRunSucceeded = "succeeded"
+	HookRunFailed    = "failed"
+	// HookRunInterrupted is the status of a run whose process exited
+	// without recording a result, e.g. because grove was killed
+	HookRunInterrupted = "interrupted"
+
+	// maxHookRunsPerWorkTree is the number of hook runs kept for each
+	// worktree, older runs are removed.
+	maxHookRunsPerWorkTree = 100
+	// repositoryHookRunsDirectoryName holds the runs of hooks that are not
+	// run for a worktree, e.g. after-prune.
+	repositoryHookRunsDirectoryName = "_repository"
+)
+
+var (
+	ErrHookRunNotFound = errors.New("hook run not found")
+)
+
+// HookRun is the record of a hook's execution. It's stored as JSON in the
+// `.grove/hooks/<worktree>` directory next to the log of the hook's output.
+type HookRun struct {
+	ID         string           `json:"id"`
+	Event      config.HookEvent `json:"event"`
+	Branch     string           `json:"branch"`
+	Name       string           `json:"name"`
+	Shell      string           `json:"shell"`
+	ShellMode  config.ShellMode `json:"shellMode"`
+	Command    string           `json:"command"`
+	Dir        string           `json:"dir"`
+	Env        []string         `json:"env,omitempty"`
+	Timeout    time.Duration    `json:"timeout,omitempty"`
+	Background bool             `json:"background"`
+	LogPath    string           `json:"logPath"`
+	PID        int              `json:"pid,omitempty"`
+	StartedAt  time.Time        `json:"startedAt"`
+	FinishedAt *time.Time       `json:"finishedAt,omitempty"`
+	ExitCode   *int             `json:"exitCode,omitempty"`
+	Error      string           `json:"error,omitempty"`
+
+	path string
+}
+
+// Status returns whether the hook is running, succeeded, failed or was
+// interrupted.
+func (r HookRun) Status() string {
+	switch {
+	case r.FinishedAt == nil && r.PID > 0 && !util.ProcessExists(r.PID):
+		return HookRunInterrupted
+	case r.FinishedAt == nil:
+		return HookRunRunning
+	case r.ExitCode != nil && *r.ExitCode == 0:
+		return HookRunSucceeded
+	default:
+		return HookRunFailed
+	}
+}
+
+// Duration returns how long the hook ran for, or has been running for.
+func (r HookRun) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return time.Since(r.StartedAt)
+	}
+
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Output returns the recorded output of the hook.
+func (r HookRun) Output() (string, error) {
+	output, err := os.ReadFile(r.LogPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+// finish records the result of the hook's execution.
+func (r *HookRun) finish(ctx context.Context, err error) error {
+	finishedAt := time.Now()
+	exitCode := 0
+	if err != nil {
+		exitCode = -1
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+
+		r.Error = err.Error()
+		if ctx.Err() == context.DeadlineExceeded {
+			r.Error = "timed out after " + r.Timeout.String()
+		}
+	}
+
+	r.FinishedAt = &finishedAt
+	r.ExitCode = &exitCode
+
+	return r.save()
+}
+
+func (r HookRun) save() error {
+	marshaled, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The command and its environment may contain secrets
+	return os.WriteFile(r.path, marshaled, 0600)
+}
+
+func loadHookRun(path string) (*HookRun, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var run HookRun
+	err = json.Unmarshal(data, &run)
+	if err != nil {
+		return nil, err
+	}
+
+	run.path = path
+
+	return &run, nil
+}
+
+func (grove *Grove) hooksPath() string {
+	return filepath.Join(grove.GrovePath, HooksDirectoryName)
+}
+
+// hookRunsPath returns the directory the runs of the branch's hooks are
+// recorded in. It's named after the branch's slug followed by a hash of the
+// branch, as different branches such as `feature/x` and `feature-x` or
+// `Feature/X` share a slug.
+func (grove *Grove) hookRunsPath(branch string) string {
+	if branch == "" {
+		return filepath.Join(grove.hooksPath(), repositoryHookRunsDirectoryName)
+	}
+
+	sum := sha256.Sum256([]byte(branch))
+	name := truncate(util.Slugify(branch), 40) + "-" + hex.EncodeToString(sum[:4])
+
+	return filepath.Join(grove.hooksPath(), name)
+}
+
+// newHookRun records the start of the hook's execution. The command's
+// directory is resolved to an absolute path.
+func (grove *Grove) newHookRun(hook config.Hook, data TemplateData, cmd *util.ShellCmd) (*HookRun, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := wd
+	if cmd.Dir != "" {
+		dir = cmd.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(wd, dir)
+		}
+	}
+
+	runsPath := grove.hookRunsPath(data.Branch)
+	err = os.MkdirAll(runsPath, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	startedAt := time.Now()
+	id := startedAt.Format("20060102T150405.000000000") + "-" + truncate(util.Slugify(hook.DisplayName()), 40)
+
+	run := &HookRun{
+		ID:         id,
+		Event:      data.Event,
+		Branch:     data.Branch,
+		Name:       hook.DisplayName(),
+		Shell:      cmd.Shell,
+		ShellMode:  cmd.Mode,
+		Command:    cmd.Command,
+		Dir:        dir,
+		Timeout:    grove.hookTimeout(hook),
+		Background: hook.Background,
+		LogPath:    filepath.Join(runsPath, id+".log"),
+		PID:        os.Getpid(),
+		StartedAt:  startedAt,
+		path:       filepath.Join(runsPath, id+".json"),
+	}
+
+	// Only background runs need the environment to be re-executed, it's not
+	// kept otherwise since it commonly holds secrets
+	if hook.Background {
+		run.Env = cmd.Env
+	}
+
+	err = run.save()
+	if err != nil {
+		return nil, err
+	}
+
+	err = pruneHookRuns(runsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// pruneHookRuns removes all but the most recent runs in the directory.
+func pruneHookRuns(runsPath string) error {
+	records, err := filepath.Glob(filepath.Join(runsPath, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	if len(records) <= maxHookRunsPerWorkTree {
+		return nil
+	}
+
+	// IDs start with the time the hook started, so they sort chronologically
+	slices.Sort(records)
+	for _, record := range records[:len(records)-maxHookRunsPerWorkTree] {
+		err = os.Remove(record)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(strings.TrimSuffix(record, ".json") + ".log")
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HookRuns returns the recorded hook runs of the branch's worktree, or of
+// every worktree when branch is empty, most recent first. The branch
+// supports aliases like j/fm-3311.
+func (grove *Grove) HookRuns(ctx context.Context, branch string) ([]HookRun, error) {
+	if branch != "" {
+		var err error
+		branch, err = grove.resolveWorkTreeBranch(git.ContextWithDir(ctx, grove.RepositoryPath), branch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return grove.hookRuns(branch)
+}
+
+// hookRuns returns the recorded hook runs of the branch, or of every branch
+// when it's empty, most recent first.
+func (grove *Grove) hookRuns(branch string) ([]HookRun, error) {
+	pattern := filepath.Join(grove.hooksPath(), "*", "*.json")
+	if branch != "" {
+		pattern = filepath.Join(grove.hookRunsPath(branch), "*.json")
+	}
+
+	records, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]HookRun, 0, len(records))
+	for _, record := range records {
+		run, err := loadHookRun(record)
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, *run)
+	}
+
+	slices.SortFunc(runs, func(a, b HookRun) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+
+	return runs, nil
+}
+
+// HookRun returns the recorded hook run with the specified ID.
+func (grove *Grove) HookRun(id string) (*HookRun, error) {
+	records, err := filepath.Glob(filepath.Join(grove.hooksPath(), "*", id+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrHookRunNotFound, id)
+	}
+
+	return loadHookRun(records[0])
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return strings.TrimSuffix(s[:n], "-")
+}
@@ -0,0 +1,89 @@
+package grove
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/exec"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+// startBackgroundHook records the hook and starts a detached grove process
+// that runs it, see RunBackgroundHook.
+func (grove *Grove) startBackgroundHook(ctx context.Context, hook config.Hook, data TemplateData) error {
+	cmd, err := grove.prepareHook(hook, data)
+	if err != nil {
+		return err
+	}
+
+	run, err := grove.newHookRun(hook, data, cmd)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	process := exec.Command(exe, "hooks", "run-background", run.path)
+	util.Detach(process)
+
+	err = process.Start()
+	if err != nil {
+		return err
+	}
+
+	// grove may exit before the hook starts, the run must not be reported
+	// as interrupted meanwhile
+	run.PID = process.Process.Pid
+	err = run.save()
+	if err != nil {
+		return err
+	}
+
+	util.LogInfo(ctx, "started background hook", slog.String("event", string(data.Event)), slog.String("hook", run.Name), slog.String("id", run.ID))
+
+	return process.Process.Release()
+}
+
+// RunBackgroundHook runs the background hook recorded at recordPath, writing
+// its output to the hook's log and its result to the record.
+func RunBackgroundHook(ctx context.Context, recordPath string) error {
+	run, err := loadHookRun(recordPath)
+	if err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(run.LogPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+
+	run.PID = os.Getpid()
+	err = run.save()
+	if err != nil {
+		return err
+	}
+
+	if run.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, run.Timeout)
+		defer cancel()
+	}
+
+	err = util.ExecShellCmd(ctx, util.ShellCmd{
+		Shell:   run.Shell,
+		Mode:    run.ShellMode,
+		Command: run.Command,
+		Dir:     run.Dir,
+		Env:     run.Env,
+		Stdout:  log,
+		Stderr:  log,
+	})
+
+	return run.finish(ctx, err)
+}
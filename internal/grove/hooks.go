@@ -2,24 +2,400 @@ package grove
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
 	"github.com/jacobdrury/grove/internal/util"
 )
 
-func (grove *Grove) executeAfterCheckoutHooks(ctx context.Context) error {
-	slog.DebugContext(ctx, "executing after checkout hooks", slog.Int("numberOfHooks", len(grove.Config.Hooks.AfterCheckout)))
-	for _, hook := range grove.Config.Hooks.AfterCheckout {
-		util.LogInfo(ctx, "executing hook", slog.String("hook", hook))
+var (
+	ErrHookDependencyFailed = errors.New("hook dependency failed")
+	ErrUnknownHookEvent     = errors.New("unknown hook event")
+)
+
+type RunHooksArgs struct {
+	Event  config.HookEvent
+	Branch string // Supports aliases j/fm-3311, defaults to the worktree in the current working directory
+}
+
+// RunHooks runs the event's hooks for a worktree outside of the command that
+// normally triggers them, e.g. to retry hooks that failed during checkout.
+func (grove *Grove) RunHooks(ctx context.Context, arg RunHooksArgs) error {
+	if !slices.Contains(config.HookEvents(), arg.Event) {
+		return fmt.Errorf("%w: %s", ErrUnknownHookEvent, arg.Event)
+	}
+
+	// after-prune isn't run for a worktree
+	if arg.Event == config.HookEventAfterPrune {
+		return util.InDirectoryNoResult(grove.RepositoryPath, func() error {
+			return grove.executeHooks(ctx, arg.Event, &TemplateData{
+				RepoRoot: grove.RepositoryPath,
+			})
+		})
+	}
+
+	wt, err := grove.findWorkTree(ctx, arg.Branch)
+	if err != nil {
+		return err
+	}
+
+	data, err := grove.templateData(ctx, wt.Branch, wt.Path, arg.Event == config.HookEventAfterCreate)
+	if err != nil {
+		return err
+	}
+	data.Path = wt.Path
+
+	dir := wt.Path
+	if arg.Event == config.HookEventBeforeCheckout || arg.Event == config.HookEventAfterRemove {
+		dir = grove.RepositoryPath
+	}
+
+	return util.InDirectoryNoResult(dir, func() error {
+		return grove.executeHooks(ctx, arg.Event, data)
+	})
+}
+
+// findWorkTree returns the worktree of the branch, or the worktree in the
+// current working directory when branch is empty.
+func (grove *Grove) findWorkTree(ctx context.Context, branch string) (*git.WorkTree, error) {
+	if branch == "" {
+		path, err := git.GetTopLevel(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		wts, err := git.ListWorkTrees(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, wt := range wts {
+			if wt.Path == path && wt.Branch != "" {
+				return &wt, nil
+			}
+		}
+
+		return nil, fmt.Errorf("%w in %s", git.ErrWorkTreeNotFound, path)
+	}
+
+	return util.InDirectory(grove.RepositoryPath, func() (*git.WorkTree, error) {
+		branch, err := grove.resolveWorkTreeBranch(ctx, branch)
+		if err != nil {
+			return nil, err
+		}
+
+		wt, err := git.FindWorkTree(ctx, branch)
+		if err != nil {
+			return nil, fmt.Errorf("error finding worktree for %s: %w", branch, err)
+		}
+
+		return wt, nil
+	})
+}
+
+// hookRun tracks the execution of a single hook.
+type hookRun struct {
+	hook config.Hook
+	deps []*hookRun
+	done chan struct{}
+	err  error
+	// skipped is set when the hook did not run because a dependency failed
+	skipped bool
+}
+
+// failed reports whether hooks depending on the run should not run.
+func (r *hookRun) failed() bool {
+	return r.err != nil && !r.hook.ContinueOnError
+}
+
+// executeHooks runs the hooks configured for the event that apply to the
+// worktree in the current working directory. Hooks run in order unless they
+// are marked as parallel, in which case they run concurrently with the
+// adjacent parallel hooks once the preceding hooks and their dependencies
+// have finished. Background hooks are started without waiting for them to
+// finish.
+//
+// Hooks are rendered as Go templates with data, which is also passed to them
+// as environment variables.
+func (grove *Grove) executeHooks(ctx context.Context, event config.HookEvent, data *TemplateData) error {
+	if config.NoHooks(ctx) {
+		return nil
+	}
+
+	hooks := grove.Config.Hooks.ForEvent(event)
+
+	err := validateHookDependencies(hooks)
+	if err != nil {
+		return fmt.Errorf("invalid %s hooks: %w", event, err)
+	}
+
+	d := *data
+	d.Event = event
+
+	slog.DebugContext(ctx, "executing hooks", slog.String("event", string(event)), slog.Int("numberOfHooks", len(hooks)))
+
+	var (
+		wg   sync.WaitGroup
+		runs []*hookRun
+
+		byName = map[string]*hookRun{}
+		// lastSequential is the most recent hook that is neither parallel nor
+		// in the background, sinceSequential are the parallel hooks after it
+		lastSequential  *hookRun
+		sinceSequential []*hookRun
+	)
+
+	for _, hook := range hooks {
+		run := &hookRun{hook: hook, done: make(chan struct{})}
+		if lastSequential != nil {
+			run.deps = append(run.deps, lastSequential)
+		}
+
+		for _, name := range hook.DependsOn {
+			run.deps = append(run.deps, byName[name])
+		}
+
+		switch {
+		case hook.Background:
+		case hook.Parallel:
+			sinceSequential = append(sinceSequential, run)
+		default:
+			run.deps = append(run.deps, sinceSequential...)
+			lastSequential = run
+			sinceSequential = nil
+		}
+
+		runs = append(runs, run)
+		if !hook.Background {
+			byName[hook.DisplayName()] = run
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(run.done)
+
+			run.err = grove.runHook(ctx, run, d)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, run := range runs {
+		if run.err == nil || run.skipped {
+			continue
+		}
+
+		if !run.hook.ContinueOnError {
+			return run.err
+		}
+
+		slog.WarnContext(ctx, "hook failed, continuing", slog.String("event", string(event)), slog.String("error", run.err.Error()))
+	}
+
+	slog.DebugContext(ctx, "hooks executed", slog.String("event", string(event)))
+
+	return nil
+}
+
+// runHook waits for the run's dependencies and then executes its hook.
+func (grove *Grove) runHook(ctx context.Context, run *hookRun, data TemplateData) error {
+	for _, dep := range run.deps {
+		select {
+		case <-dep.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if dep.failed() {
+			run.skipped = true
+			return fmt.Errorf("%w: %s", ErrHookDependencyFailed, dep.hook.DisplayName())
+		}
+	}
+
+	hook := run.hook
+	if !hookApplies(hook, data) {
+		slog.DebugContext(ctx, "skipping hook", slog.String("event", string(data.Event)), slog.String("hook", hook.DisplayName()))
+		return nil
+	}
+
+	if hook.Background {
+		return grove.startBackgroundHook(ctx, hook, data)
+	}
+
+	// Hook output goes to stderr, stdout is reserved for grove's output,
+	// e.g. the worktree path printed by `grove checkout --pipe`
+	if !hook.Parallel {
+		return grove.executeHook(ctx, hook, data, os.Stderr, os.Stderr)
+	}
+
+	// Prefix the output of hooks running concurrently so it can be told apart
+	prefix := fmt.Sprintf("[%s] ", hook.DisplayName())
+	stdout := util.NewPrefixWriter(os.Stderr, prefix)
+	stderr := util.NewPrefixWriter(os.Stderr, prefix)
+
+	defer func() {
+		_ = stdout.Flush()
+		_ = stderr.Flush()
+	}()
+
+	return grove.executeHook(ctx, hook, data, stdout, stderr)
+}
+
+func (grove *Grove) executeHook(ctx context.Context, hook config.Hook, data TemplateData, stdout io.Writer, stderr io.Writer) error {
+	cmd, err := grove.prepareHook(hook, data)
+	if err != nil {
+		return err
+	}
+
+	run, err := grove.newHookRun(hook, data, cmd)
+	if err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(run.LogPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
 
-		err := util.ExecShellCmd(ctx, grove.Config.Hooks.Shell, hook)
+	// Record the output so failed hooks can be inspected later
+	cmd.Stdout = io.MultiWriter(stdout, log)
+	cmd.Stderr = io.MultiWriter(stderr, log)
+
+	// Hooks running concurrently can't share the terminal's input
+	if hook.Stdin && !hook.Parallel {
+		cmd.Stdin = os.Stdin
+	}
+
+	timeout := grove.hookTimeout(hook)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	util.LogInfo(ctx, "executing hook", slog.String("event", string(data.Event)), slog.String("hook", cmd.Command))
+
+	err = util.ExecShellCmd(ctx, *cmd)
+
+	recordErr := run.finish(ctx, err)
+	if recordErr != nil {
+		slog.WarnContext(ctx, "error recording hook run", slog.String("id", run.ID), slog.String("error", recordErr.Error()))
+	}
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s hook %s timed out after %v, see `grove hooks log %s`", data.Event, cmd.Command, timeout, run.ID)
+		}
+
+		return fmt.Errorf("error executing %s hook %s: %v, see `grove hooks log %s`", data.Event, cmd.Command, err, run.ID)
+	}
+
+	return nil
+}
+
+// prepareHook renders the hook's templates, if it opted in to them, and
+// resolves the shell it runs with.
+func (grove *Grove) prepareHook(hook config.Hook, data TemplateData) (*util.ShellCmd, error) {
+	render := func(text string) (string, error) {
+		if !hook.Template {
+			return text, nil
+		}
+
+		return util.RenderTemplate(string(data.Event), text, data)
+	}
+
+	cmd, err := render(hook.Run)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering %s hook %s: %v", data.Event, hook.Run, err)
+	}
+
+	dir, err := render(hook.WorkingDir)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering %s hook working-dir %s: %v", data.Event, hook.WorkingDir, err)
+	}
+
+	env := data.Env()
+	for key, value := range hook.Env {
+		value, err = render(value)
 		if err != nil {
-			return fmt.Errorf("error executing hook %s: %v", hook, err)
+			return nil, fmt.Errorf("error rendering %s hook env %s: %v", data.Event, key, err)
 		}
+
+		env = append(env, key+"="+value)
 	}
 
-	slog.DebugContext(ctx, "after checkout hooks executed")
+	shell := grove.Config.Hooks.Shell
+	if hook.Shell != "" {
+		shell = hook.Shell
+	}
+
+	mode := grove.Config.Hooks.ShellMode
+	if hook.ShellMode != "" {
+		mode = hook.ShellMode
+	}
+
+	return &util.ShellCmd{
+		Shell:   shell,
+		Mode:    mode,
+		Command: cmd,
+		Dir:     dir,
+		Env:     env,
+	}, nil
+}
+
+// hookTimeout returns the maximum duration the hook may run for, zero if
+// there is no limit.
+func (grove *Grove) hookTimeout(hook config.Hook) time.Duration {
+	if hook.Timeout > 0 {
+		return hook.Timeout
+	}
+
+	return grove.Config.Hooks.Timeout
+}
+
+// validateHookDependencies ensures every dependency names an earlier hook
+// that doesn't run in the background, which also rules out cycles.
+func validateHookDependencies(hooks []config.Hook) error {
+	earlier := map[string]bool{}
+	for _, hook := range hooks {
+		for _, dep := range hook.DependsOn {
+			if !earlier[dep] {
+				return fmt.Errorf("hook %q depends on %q, which is not the name of an earlier hook that runs in the foreground", hook.DisplayName(), dep)
+			}
+		}
+
+		if !hook.Background {
+			earlier[hook.DisplayName()] = true
+		}
+	}
 
 	return nil
 }
+
+// hookApplies reports whether the hook's only-on and when conditions match
+// the worktree.
+func hookApplies(hook config.Hook, data TemplateData) bool {
+	switch hook.OnlyOn {
+	case config.HookOnlyOnNew:
+		if !data.IsNew {
+			return false
+		}
+	case config.HookOnlyOnExisting:
+		if data.IsNew {
+			return false
+		}
+	}
+
+	return hook.When.Matches(data.Branch, runtime.GOOS)
+}
@@ -0,0 +1,544 @@
+package grove
+
+import (
+	"context"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+func TestValidateHookDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks []config.Hook
+		valid bool
+	}{
+		{
+			name:  "earlier hook",
+			hooks: []config.Hook{{Name: "install", Run: "npm install"}, {Run: "npm run build", DependsOn: []string{"install"}}},
+			valid: true,
+		},
+		{
+			name:  "unnamed hook",
+			hooks: []config.Hook{{Run: "npm install"}, {Run: "npm run build", DependsOn: []string{"npm install"}}},
+			valid: true,
+		},
+		{
+			name:  "later hook",
+			hooks: []config.Hook{{Run: "npm run build", DependsOn: []string{"install"}}, {Name: "install", Run: "npm install"}},
+			valid: false,
+		},
+		{
+			name:  "background hook",
+			hooks: []config.Hook{{Name: "install", Run: "npm install", Background: true}, {Run: "npm run build", DependsOn: []string{"install"}}},
+			valid: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := validateHookDependencies(tc.hooks); (err == nil) != tc.valid {
+				t.Errorf("validateHookDependencies() error = %v, valid = %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestExecuteHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	record := func(s string) string {
+		return "echo " + s + " >> " + out
+	}
+
+	grove := Grove{
+		GrovePath: t.TempDir(),
+		Config: &config.Config{
+			Hooks: config.Hooks{
+				Shell: "/bin/sh",
+				AfterCreate: []config.Hook{
+					{Name: "first", Run: record("first")},
+					{Name: "fails", Run: "exit 1", Parallel: true},
+					{Name: "independent", Run: record("independent"), Parallel: true},
+					{Name: "dependent", Run: record("dependent"), Parallel: true, DependsOn: []string{"fails"}},
+					{Name: "last", Run: record("last")},
+				},
+			},
+		},
+	}
+
+	err := grove.executeHooks(context.Background(), config.HookEventAfterCreate, &TemplateData{})
+	if err == nil || !strings.Contains(err.Error(), "exit 1") {
+		t.Fatalf("executeHooks() error = %v, want the failing hook's error", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Fields(string(data)); strings.Join(got, ",") != "first,independent" {
+		t.Errorf("hooks run = %v, want [first independent]", got)
+	}
+
+	runs, err := grove.hookRuns("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := map[string]string{}
+	for _, run := range runs {
+		statuses[run.Name] = run.Status()
+	}
+
+	want := map[string]string{"first": HookRunSucceeded, "fails": HookRunFailed, "independent": HookRunSucceeded}
+	if !maps.Equal(statuses, want) {
+		t.Errorf("recorded runs = %v, want %v", statuses, want)
+	}
+}
+
+func TestPrepareHook(t *testing.T) {
+	grove := Grove{Config: config.DefaultConfig()}
+	data := TemplateData{Event: config.HookEventAfterCreate, Branch: "feature/x", Port: 3001}
+
+	tests := []struct {
+		name    string
+		hook    config.Hook
+		want    string
+		env     string
+		wantErr bool
+	}{
+		{
+			name: "plain",
+			hook: config.Hook{Run: "docker ps --format '{{.Names}}'", Env: map[string]string{"FORMAT": "{{.ID}}"}},
+			want: "docker ps --format '{{.Names}}'",
+			env:  "FORMAT={{.ID}}",
+		},
+		{
+			name: "template",
+			hook: config.Hook{Run: "npm run dev -- --port {{.Port}}", Env: map[string]string{"BRANCH": "{{.Branch}}"}, Template: true},
+			want: "npm run dev -- --port 3001",
+			env:  "BRANCH=feature/x",
+		},
+		{
+			name:    "invalid template",
+			hook:    config.Hook{Run: "docker ps --format '{{.Names}}'", Template: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := grove.prepareHook(tc.hook, data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("prepareHook() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr {
+				return
+			}
+
+			if cmd.Command != tc.want {
+				t.Errorf("prepareHook() command = %q, want %q", cmd.Command, tc.want)
+			}
+
+			if !slices.Contains(cmd.Env, tc.env) {
+				t.Errorf("prepareHook() env = %v, want it to contain %q", cmd.Env, tc.env)
+			}
+		})
+	}
+}
+
+func TestHookRunOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	grove := Grove{
+		GrovePath: t.TempDir(),
+		Config: &config.Config{
+			Hooks: config.Hooks{
+				Shell:       "/bin/sh",
+				AfterCreate: []config.Hook{{Name: "install", Run: "echo installing; echo failed >&2; exit 3", Env: map[string]string{"TOKEN": "secret"}}},
+			},
+		},
+	}
+
+	_ = grove.executeHooks(context.Background(), config.HookEventAfterCreate, &TemplateData{Branch: "feature/login"})
+
+	runs, err := grove.hookRuns("feature/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(runs) != 1 {
+		t.Fatalf("recorded %d runs, want 1", len(runs))
+	}
+
+	run, err := grove.HookRun(runs[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if run.ExitCode == nil || *run.ExitCode != 3 {
+		t.Errorf("exit code = %v, want 3", run.ExitCode)
+	}
+
+	// Only background runs need their environment, which may hold secrets
+	if len(run.Env) != 0 {
+		t.Errorf("env = %v, want none recorded", run.Env)
+	}
+
+	for _, path := range []string{run.path, run.LogPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s mode = %v, want 0600", filepath.Base(path), info.Mode().Perm())
+		}
+	}
+
+	output, err := run.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(output, "installing") || !strings.Contains(output, "failed") {
+		t.Errorf("output = %q, want stdout and stderr", output)
+	}
+}
+
+func TestHookRunsPerBranch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	grove := Grove{
+		GrovePath: t.TempDir(),
+		Config: &config.Config{
+			Hooks: config.Hooks{
+				Shell:       "/bin/sh",
+				AfterCreate: []config.Hook{{Name: "install", Run: "true"}},
+			},
+		},
+	}
+
+	// The branches share a slug, their runs must still be kept apart
+	branches := []string{"feature/x", "feature-x", "Feature/X"}
+	for _, branch := range branches {
+		err := grove.executeHooks(context.Background(), config.HookEventAfterCreate, &TemplateData{Branch: branch})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, branch := range branches {
+		runs, err := grove.hookRuns(branch)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(runs) != 1 || runs[0].Branch != branch {
+			t.Errorf("hookRuns(%q) = %v, want only the branch's run", branch, runs)
+		}
+	}
+
+	all, err := grove.hookRuns("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all) != len(branches) {
+		t.Errorf("hookRuns() returned %d runs, want %d", len(all), len(branches))
+	}
+}
+
+func TestHookRunStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the exited process is started with true")
+	}
+
+	// The process has exited and been reaped once Run returns
+	exited := exec.Command("true")
+	err := exited.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exitCode := 1
+	finishedAt := time.Now()
+
+	tests := []struct {
+		name string
+		run  HookRun
+		want string
+	}{
+		{name: "running", run: HookRun{PID: os.Getpid()}, want: HookRunRunning},
+		{name: "interrupted", run: HookRun{PID: exited.Process.Pid}, want: HookRunInterrupted},
+		{name: "failed", run: HookRun{PID: exited.Process.Pid, FinishedAt: &finishedAt, ExitCode: &exitCode}, want: HookRunFailed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.run.Status(); got != tc.want {
+				t.Errorf("Status() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHookRunsAlias(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	const repo = "/repo"
+
+	grove := Grove{
+		RepositoryPath: repo,
+		GrovePath:      t.TempDir(),
+		Config: &config.Config{
+			BranchResolver: config.BranchResolver{
+				BranchDelimiter:     "/",
+				BranchPrefixAliases: map[config.BranchPrefixAlias]config.BranchPrefix{"f": "feature"},
+			},
+			Hooks: config.Hooks{
+				Shell:       "/bin/sh",
+				AfterCreate: []config.Hook{{Name: "install", Run: "true"}},
+			},
+		},
+	}
+
+	err := grove.executeHooks(context.Background(), config.HookEventAfterCreate, &TemplateData{Branch: "feature/login"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &fakeRunner{responses: map[string]string{
+		"-C " + repo + " worktree list --porcelain -z": "worktree /repo\x00HEAD abc\x00branch refs/heads/main\x00\x00" +
+			"worktree /repo/worktrees/feature/login\x00HEAD def\x00branch refs/heads/feature/login\x00\x00",
+	}}
+	ctx := git.ContextWithRunner(context.Background(), runner)
+
+	runs, err := grove.HookRuns(ctx, "f/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(runs) != 1 || runs[0].Branch != "feature/login" {
+		t.Errorf("HookRuns(f/login) = %v, want the feature/login run", runs)
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		hook     config.Hook
+		timedOut string
+	}{
+		{name: "hook", hook: config.Hook{Run: "sleep 5", Timeout: 100 * time.Millisecond}, timedOut: "100ms"},
+		{name: "global", timeout: 100 * time.Millisecond, hook: config.Hook{Run: "sleep 5"}, timedOut: "100ms"},
+		{name: "hook overrides global", timeout: 100 * time.Millisecond, hook: config.Hook{Run: "sleep 0.3", Timeout: 5 * time.Second}},
+		{name: "no timeout", hook: config.Hook{Run: "sleep 0.3"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			grove := Grove{
+				GrovePath: t.TempDir(),
+				Config: &config.Config{
+					Hooks: config.Hooks{
+						Shell:       "/bin/sh",
+						Timeout:     tc.timeout,
+						AfterCreate: []config.Hook{tc.hook},
+					},
+				},
+			}
+
+			start := time.Now()
+			err := grove.executeHooks(context.Background(), config.HookEventAfterCreate, &TemplateData{})
+			if tc.timedOut == "" {
+				if err != nil {
+					t.Fatalf("executeHooks() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), "timed out after "+tc.timedOut) {
+				t.Fatalf("executeHooks() error = %v, want timed out after %s", err, tc.timedOut)
+			}
+
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("hook was stopped after %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	grove, _ := newTestRepo(t)
+
+	out := filepath.Join(t.TempDir(), "out")
+	grove.Config.Hooks = config.Hooks{
+		Shell:       "/bin/sh",
+		AfterSwitch: []config.Hook{{Name: "port", Run: "echo $GROVE_PORT >> " + out}},
+	}
+
+	err := grove.savePorts(ports{"main": 3000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Running hooks is read-only, a worktree without a port isn't allocated
+	// one
+	err = grove.RunHooks(context.Background(), RunHooksArgs{Event: config.HookEventAfterSwitch, Branch: "feature/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPorts(t, grove, ports{"main": 3000})
+
+	err = grove.savePorts(ports{"main": 3000, "feature/x": 3005})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = grove.RunHooks(context.Background(), RunHooksArgs{Event: config.HookEventAfterSwitch, Branch: "feature/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Fields(string(data)); strings.Join(got, ",") != "3005" {
+		t.Errorf("ports passed to hooks = %v, want [3005]", got)
+	}
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with /bin/sh")
+	}
+
+	grove, _ := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+
+	out := filepath.Join(t.TempDir(), "out")
+	record := []config.Hook{{Name: "record", Run: "echo $GROVE_EVENT $GROVE_BRANCH $GROVE_IS_NEW >> " + out}}
+	grove.Config.Hooks = config.Hooks{
+		Shell:          "/bin/sh",
+		BeforeCheckout: record,
+		AfterCreate:    record,
+		AfterSwitch:    record,
+		AfterCheckout:  record,
+		BeforeRemove:   record,
+		AfterRemove:    record,
+		AfterPrune:     record,
+	}
+
+	// recorded returns the events recorded since it was last called
+	recorded := func() []string {
+		t.Helper()
+
+		data, err := os.ReadFile(out)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+
+		if err := os.RemoveAll(out); err != nil {
+			t.Fatal(err)
+		}
+
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	ctx := context.Background()
+	steps := []struct {
+		name string
+		run  func() error
+		want []string
+	}{
+		{
+			name: "checkout new worktree",
+			run: func() error {
+				_, err := grove.Checkout(ctx, CheckoutArgs{Branch: "feature/new", New: true})
+				return err
+			},
+			want: []string{
+				"before-checkout feature/new true",
+				"after-create feature/new true",
+				"after-checkout feature/new true",
+			},
+		},
+		{
+			name: "checkout existing worktree",
+			run: func() error {
+				_, err := grove.Checkout(ctx, CheckoutArgs{Branch: "feature/new"})
+				return err
+			},
+			want: []string{
+				"before-checkout feature/new false",
+				"after-switch feature/new false",
+				"after-checkout feature/new false",
+			},
+		},
+		{
+			name: "remove",
+			run: func() error {
+				_, err := grove.Remove(ctx, RemoveArgs{Branch: "feature/new", Force: true})
+				return err
+			},
+			want: []string{
+				"before-remove feature/new",
+				"after-remove feature/new",
+			},
+		},
+		{
+			name: "prune",
+			run: func() error {
+				_, err := grove.Prune(ctx, PruneArgs{})
+				return err
+			},
+			want: []string{"after-prune"},
+		},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if got := recorded(); !slices.Equal(got, step.want) {
+			t.Errorf("%s: hooks run = %q, want %q", step.name, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package grove
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+)
+
+// WorkTreeStatus describes the state of a single worktree.
+type WorkTreeStatus struct {
+	Path              string    `json:"path"`
+	Branch            string    `json:"branch"`
+	Head              string    `json:"head"`
+	Bare              bool      `json:"bare"`
+	Detached          bool      `json:"detached"`
+	Dirty             bool      `json:"dirty"`
+	Upstream          string    `json:"upstream,omitempty"`
+	Ahead             int       `json:"ahead"`
+	Behind            int       `json:"behind"`
+	LastCommitDate    time.Time `json:"lastCommitDate"`
+	LastCommitSubject string    `json:"lastCommitSubject"`
+	Locked            bool      `json:"locked"`
+	LockReason        string    `json:"lockReason,omitempty"`
+	Prunable          bool      `json:"prunable"`
+	PrunableReason    string    `json:"prunableReason,omitempty"`
+	Merged            bool      `json:"merged"`
+}
+
+// List returns the status of every worktree in the repository.
+func (grove *Grove) List(ctx context.Context) ([]WorkTreeStatus, error) {
+	return util.InDirectory(grove.RepositoryPath, func() ([]WorkTreeStatus, error) {
+		wts, err := git.ListWorkTrees(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses := make([]WorkTreeStatus, 0, len(wts))
+		for _, wt := range wts {
+			status, err := grove.workTreeStatus(ctx, wt)
+			if err != nil {
+				return nil, err
+			}
+
+			statuses = append(statuses, *status)
+		}
+
+		return statuses, nil
+	})
+}
+
+func (grove *Grove) workTreeStatus(ctx context.Context, wt git.WorkTree) (*WorkTreeStatus, error) {
+	slog.DebugContext(ctx, "getting worktree status", slog.String("path", wt.Path))
+
+	status := &WorkTreeStatus{
+		Path:           wt.Path,
+		Branch:         wt.Branch,
+		Head:           wt.Head,
+		Bare:           wt.Bare,
+		Detached:       wt.Detached,
+		Locked:         wt.Locked,
+		LockReason:     wt.LockReason,
+		Prunable:       wt.Prunable,
+		PrunableReason: wt.PrunableReason,
+	}
+
+	// A bare repository has no working tree or checked out commit
+	if wt.Bare {
+		return status, nil
+	}
+
+	commit, err := git.GetCommit(ctx, wt.Head)
+	if err != nil {
+		return nil, err
+	}
+
+	status.LastCommitDate = commit.Date
+	status.LastCommitSubject = commit.Subject
+
+	var upstream string
+	if !wt.Detached {
+		upstream, err = git.GetUpstream(ctx, wt.Branch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if upstream != "" {
+		status.Upstream = upstream
+
+		// The upstream may have been deleted on the remote, in which case
+		// there is nothing to compare against.
+		ahead, behind, err := git.CountAheadBehind(ctx, wt.Head, upstream)
+		if err == nil {
+			status.Ahead = ahead
+			status.Behind = behind
+		}
+	}
+
+	if !wt.Detached {
+		merged, err := grove.isMerged(ctx, wt, upstream)
+		if err != nil {
+			slog.DebugContext(ctx, "unable to determine if branch is merged", slog.String("branch", wt.Branch), slog.String("error", err.Error()))
+		}
+
+		status.Merged = merged
+	}
+
+	// A prunable worktree no longer exists on disk
+	if !wt.Prunable {
+		dirty, err := util.InDirectory(wt.Path, func() (bool, error) {
+			return git.HasUncommittedChanges(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		status.Dirty = dirty
+	}
+
+	return status, nil
+}
+
+// isMerged reports whether the worktree's branch has been merged into its
+// base branch, preferring the remote-tracking branch like new branches do.
+// The base branch itself and branches without an upstream that haven't moved
+// since they were created are never considered merged.
+func (grove *Grove) isMerged(ctx context.Context, wt git.WorkTree, upstream string) (bool, error) {
+	base, err := grove.baseBranch(ctx, wt.Branch)
+	if err != nil {
+		return false, err
+	}
+
+	if wt.Branch == base {
+		return false, nil
+	}
+
+	// A branch checked out from the remote hasn't moved either, but may
+	// have been merged before
+	if upstream == "" {
+		untouched, err := isUntouched(ctx, wt)
+		if err != nil || untouched {
+			return false, err
+		}
+	}
+
+	ref, err := grove.baseRef(ctx, wt.Branch)
+	if err != nil {
+		return false, err
+	}
+
+	return git.IsAncestor(ctx, wt.Head, ref)
+}
+
+// isUntouched reports whether the worktree's branch still points to the
+// commit it was created at, according to its reflog.
+func isUntouched(ctx context.Context, wt git.WorkTree) (bool, error) {
+	reflog, err := git.ListReflog(ctx, wt.Ref)
+	if err != nil {
+		return false, err
+	}
+
+	return len(reflog) > 0 && lo.EveryBy(reflog, func(commit string) bool { return commit == wt.Head }), nil
+}
@@ -0,0 +1,125 @@
+package grove
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+func TestList(t *testing.T) {
+	grove, wtPath := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+	repo := grove.RepositoryPath
+	ctx := context.Background()
+
+	findStatus := func(t *testing.T, branch string) WorkTreeStatus {
+		t.Helper()
+
+		statuses, err := grove.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, status := range statuses {
+			if status.Branch == branch {
+				return status
+			}
+		}
+
+		t.Fatalf("List() has no worktree of %s: %+v", branch, statuses)
+		return WorkTreeStatus{}
+	}
+
+	// A branch that was just created contains nothing to merge
+	if status := findStatus(t, "feature/x"); status.Merged || status.Dirty || status.Upstream != "" {
+		t.Errorf("new branch status = %+v, want unmerged, clean and without upstream", status)
+	}
+
+	if status := findStatus(t, "main"); status.Merged {
+		t.Errorf("base branch is merged")
+	}
+
+	runGit(t, wtPath, "commit", "-q", "--allow-empty", "-m", "change")
+	runGit(t, wtPath, "push", "-q", "-u", "origin", "feature/x")
+	if status := findStatus(t, "feature/x"); status.Merged || status.Upstream != "origin/feature/x" || status.LastCommitSubject != "change" {
+		t.Errorf("status = %+v, want unmerged and tracking origin/feature/x", status)
+	}
+
+	runGit(t, wtPath, "commit", "-q", "--allow-empty", "-m", "unpushed")
+	err := os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("x"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := findStatus(t, "feature/x"); !status.Dirty || status.Ahead != 1 || status.Behind != 0 {
+		t.Errorf("status = %+v, want dirty and 1 ahead", status)
+	}
+
+	// Merged into the remote base branch while the local one is out of date
+	runGit(t, wtPath, "push", "-q", "origin", "feature/x:main")
+	runGit(t, repo, "fetch", "-q")
+	if status := findStatus(t, "feature/x"); !status.Merged {
+		t.Errorf("branch merged into origin/main isn't merged")
+	}
+
+	// A branch checked out from the remote after it was merged
+	checkoutMergedRemoteBranch(t, grove, "feature/remote")
+	if status := findStatus(t, "feature/remote"); !status.Merged {
+		t.Errorf("branch checked out from the remote after it was merged isn't merged")
+	}
+}
+
+func TestWorkTreeStatus(t *testing.T) {
+	grove, wtPath := newTestRepo(t)
+	grove.Config.BaseBranch = "main"
+	ctx := context.Background()
+
+	head := runGit(t, wtPath, "rev-parse", "HEAD")
+
+	tests := []struct {
+		name string
+		wt   git.WorkTree
+		want WorkTreeStatus
+	}{
+		{
+			name: "bare",
+			wt:   git.WorkTree{Path: grove.RepositoryPath, Bare: true},
+			want: WorkTreeStatus{Path: grove.RepositoryPath, Bare: true},
+		},
+		{
+			name: "detached",
+			wt:   git.WorkTree{Path: wtPath, Head: head, Detached: true},
+			want: WorkTreeStatus{Path: wtPath, Head: head, Detached: true, LastCommitSubject: "initial"},
+		},
+		{
+			name: "prunable",
+			wt:   git.WorkTree{Path: filepath.Join(t.TempDir(), "gone"), Branch: "feature/x", Ref: "refs/heads/feature/x", Head: head, Prunable: true, PrunableReason: "gitdir file points to non-existent location"},
+			want: WorkTreeStatus{Branch: "feature/x", Head: head, Prunable: true, PrunableReason: "gitdir file points to non-existent location", LastCommitSubject: "initial"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, err := util.InDirectory(grove.RepositoryPath, func() (*WorkTreeStatus, error) {
+				return grove.workTreeStatus(ctx, tc.wt)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Paths of missing worktrees and commit dates aren't compared
+			status.LastCommitDate = tc.want.LastCommitDate
+			if tc.wt.Prunable {
+				status.Path = ""
+			}
+
+			if *status != tc.want {
+				t.Errorf("workTreeStatus() = %+v, want %+v", *status, tc.want)
+			}
+		})
+	}
+}
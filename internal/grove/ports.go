@@ -0,0 +1,140 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/jacobdrury/grove/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrNoPortsAvailable = errors.New("no ports available")
+)
+
+// ports maps each branch to the port allocated to its worktree.
+type ports map[string]int
+
+func (grove *Grove) portsPath() string {
+	return filepath.Join(grove.GrovePath, PortsFileName)
+}
+
+func (grove *Grove) loadPorts() (ports, error) {
+	data, err := os.ReadFile(grove.portsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ports{}, nil
+		}
+
+		return nil, err
+	}
+
+	p := ports{}
+	err = yaml.Unmarshal(data, &p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", PortsFileName, err)
+	}
+
+	return p, nil
+}
+
+func (grove *Grove) savePorts(p ports) error {
+	marshaled, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(grove.portsPath(), marshaled, 0644)
+}
+
+// lookupPort returns the port allocated to the branch's worktree, zero if it
+// doesn't have one.
+func (grove *Grove) lookupPort(branch string) (int, error) {
+	p, err := grove.loadPorts()
+	if err != nil {
+		return 0, err
+	}
+
+	return p[branch], nil
+}
+
+// allocatePort returns the port allocated to the branch's worktree,
+// allocating and saving the lowest free port in the configured range if the
+// branch doesn't have one yet. It must only be called once the worktree
+// exists, or its port would be reclaimed by the next allocation.
+func (grove *Grove) allocatePort(ctx context.Context, branch string) (int, error) {
+	port, p, err := grove.choosePort(ctx, branch)
+	if err != nil || p == nil {
+		return port, err
+	}
+
+	return port, grove.savePorts(p)
+}
+
+// choosePort returns the port of the branch's worktree. Branches without one
+// are given the lowest free port, after reclaiming the ports of branches
+// that no longer have a worktree, e.g. because a checkout failed or it was
+// removed with git directly. The changed allocations are returned to be
+// saved, nil if the branch already had a port.
+func (grove *Grove) choosePort(ctx context.Context, branch string) (int, ports, error) {
+	p, err := grove.loadPorts()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if port, ok := p[branch]; ok {
+		return port, nil, nil
+	}
+
+	wts, err := git.ListWorkTrees(git.ContextWithDir(ctx, grove.RepositoryPath))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	branches := workTreeBranches(wts)
+	for b := range p {
+		if !slices.Contains(branches, b) {
+			slog.DebugContext(ctx, "reclaiming port", slog.String("branch", b), slog.Int("port", p[b]))
+			delete(p, b)
+		}
+	}
+
+	used := make(map[int]bool, len(p))
+	for _, port := range p {
+		used[port] = true
+	}
+
+	r := grove.Config.PortRange
+	for port := r.Start; port <= r.End; port++ {
+		if used[port] {
+			continue
+		}
+
+		p[branch] = port
+
+		return port, p, nil
+	}
+
+	return 0, nil, fmt.Errorf("%w in range %d-%d", ErrNoPortsAvailable, r.Start, r.End)
+}
+
+// releasePort frees the port allocated to the branch's worktree.
+func (grove *Grove) releasePort(branch string) error {
+	p, err := grove.loadPorts()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := p[branch]; !ok {
+		return nil
+	}
+
+	delete(p, branch)
+
+	return grove.savePorts(p)
+}
@@ -0,0 +1,165 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+func TestAllocatePort(t *testing.T) {
+	const repo = "/repo"
+
+	// feature/gone has a port but no worktree, e.g. because its checkout
+	// failed
+	workTrees := "worktree /repo\x00HEAD abc\x00branch refs/heads/main\x00\x00" +
+		"worktree /repo/worktrees/feature/x\x00HEAD def\x00branch refs/heads/feature/x\x00\x00" +
+		"worktree /repo/worktrees/feature/y\x00HEAD def\x00branch refs/heads/feature/y\x00\x00"
+
+	tests := []struct {
+		name   string
+		ports  ports
+		branch string
+		end    int
+		want   int
+		saved  ports
+		err    error
+	}{
+		{
+			name:   "first",
+			ports:  ports{},
+			branch: "feature/x",
+			want:   3000,
+			saved:  ports{"feature/x": 3000},
+		},
+		{
+			name:   "existing",
+			ports:  ports{"feature/x": 3005},
+			branch: "feature/x",
+			want:   3005,
+			saved:  ports{"feature/x": 3005},
+		},
+		{
+			name:   "lowest free",
+			ports:  ports{"main": 3000, "feature/x": 3002},
+			branch: "feature/y",
+			want:   3001,
+			saved:  ports{"main": 3000, "feature/x": 3002, "feature/y": 3001},
+		},
+		{
+			name:   "reclaims ports without worktree",
+			ports:  ports{"feature/gone": 3000, "feature/x": 3001},
+			branch: "feature/y",
+			want:   3000,
+			saved:  ports{"feature/x": 3001, "feature/y": 3000},
+		},
+		{
+			name:   "none available",
+			ports:  ports{"main": 3000, "feature/x": 3001},
+			branch: "feature/y",
+			end:    3001,
+			err:    ErrNoPortsAvailable,
+			saved:  ports{"main": 3000, "feature/x": 3001},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			grove := &Grove{
+				Config:         config.DefaultConfig(),
+				RepositoryPath: repo,
+				GrovePath:      t.TempDir(),
+			}
+
+			if tc.end != 0 {
+				grove.Config.PortRange.End = tc.end
+			}
+
+			err := grove.savePorts(tc.ports)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			runner := &fakeRunner{responses: map[string]string{
+				"-C " + repo + " worktree list --porcelain -z": workTrees,
+			}}
+			ctx := git.ContextWithRunner(context.Background(), runner)
+
+			// Choosing the port must not allocate it
+			port, _, err := grove.choosePort(ctx, tc.branch)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("choosePort() error = %v, want %v", err, tc.err)
+			}
+
+			if port != tc.want {
+				t.Errorf("choosePort() = %v, want %v", port, tc.want)
+			}
+
+			if port, _ := grove.lookupPort(tc.branch); port != tc.ports[tc.branch] {
+				t.Errorf("lookupPort() = %v, want %v", port, tc.ports[tc.branch])
+			}
+
+			assertPorts(t, grove, tc.ports)
+
+			port, err = grove.allocatePort(ctx, tc.branch)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("allocatePort() error = %v, want %v", err, tc.err)
+			}
+
+			if port != tc.want {
+				t.Errorf("allocatePort() = %v, want %v", port, tc.want)
+			}
+
+			assertPorts(t, grove, tc.saved)
+		})
+	}
+}
+
+func TestReleasePort(t *testing.T) {
+	grove := &Grove{GrovePath: t.TempDir()}
+
+	// Releasing without any allocations doesn't create the file
+	err := grove.releasePort("feature/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(grove.portsPath()); !os.IsNotExist(err) {
+		t.Errorf("%s was created", PortsFileName)
+	}
+
+	err = grove.savePorts(ports{"feature/x": 3000, "feature/y": 3001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = grove.releasePort("feature/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPorts(t, grove, ports{"feature/y": 3001})
+}
+
+func assertPorts(t *testing.T, grove *Grove, want ports) {
+	t.Helper()
+
+	got, err := grove.loadPorts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("ports = %v, want %v", got, want)
+	}
+
+	for branch, port := range want {
+		if got[branch] != port {
+			t.Errorf("ports = %v, want %v", got, want)
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package grove
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+)
+
+type PruneArgs struct {
+	DryRun  bool   // Report what would be pruned without pruning it
+	Verbose bool   // Report all removals
+	Expire  string // Only prune worktrees older than this time, e.g. 2.weeks.ago
+}
+
+// Prune prunes worktrees that no longer exist on disk, forgets the ports,
+// seeded files and recent use of their branches and runs the after-prune
+// hooks.
+func (grove *Grove) Prune(ctx context.Context, arg PruneArgs) (*git.Result, error) {
+	return util.InDirectory(grove.RepositoryPath, func() (*git.Result, error) {
+		res, err := git.PruneWorkTrees(ctx, arg.DryRun, arg.Verbose, arg.Expire)
+		if err != nil {
+			return nil, err
+		}
+
+		if arg.DryRun {
+			return res, nil
+		}
+
+		err = grove.forgetRemovedWorkTrees(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = grove.executeHooks(ctx, config.HookEventAfterPrune, &TemplateData{
+			RepoRoot: grove.RepositoryPath,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	})
+}
+
+// forgetRemovedWorkTrees forgets the port, seeded files and recent use of
+// branches that no longer have a worktree.
+func (grove *Grove) forgetRemovedWorkTrees(ctx context.Context) error {
+	wts, err := git.ListWorkTrees(ctx)
+	if err != nil {
+		return err
+	}
+
+	p, err := grove.loadPorts()
+	if err != nil {
+		return err
+	}
+
+	s, err := grove.loadSeeded()
+	if err != nil {
+		return err
+	}
+
+	r, err := grove.loadRecent()
+	if err != nil {
+		return err
+	}
+
+	branches := workTreeBranches(wts)
+	known := lo.Uniq(slices.Concat(lo.Keys(p), lo.Keys(s), lo.Keys(r)))
+	for _, branch := range known {
+		if slices.Contains(branches, branch) {
+			continue
+		}
+
+		slog.DebugContext(ctx, "forgetting removed worktree", slog.String("branch", branch))
+
+		err = grove.releasePort(branch)
+		if err != nil {
+			return err
+		}
+
+		err = grove.forgetSeeded(branch)
+		if err != nil {
+			return err
+		}
+
+		err = grove.forgetRecent(branch)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package grove
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestPruneForgetsRemovedWorkTrees(t *testing.T) {
+	grove, wtPath := newTestRepo(t)
+	ctx := context.Background()
+
+	err := grove.savePorts(ports{"main": 3000, "feature/x": 3001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := seededFile{Mode: config.SeedModeSymlink, Source: ".env"}
+	err = grove.saveSeeded(seeded{"main": {".env": file}, "feature/x": {".env": file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = grove.saveRecent(recent{"main": time.Now(), "feature/x": time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.RemoveAll(wtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A dry run only reports what would be pruned
+	_, err = grove.Prune(ctx, PruneArgs{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPorts(t, grove, ports{"main": 3000, "feature/x": 3001})
+
+	_, err = grove.Prune(ctx, PruneArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPorts(t, grove, ports{"main": 3000})
+
+	s, err := grove.loadSeeded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := s["feature/x"]; ok || len(s) != 1 {
+		t.Errorf("seeded = %v, want only main", s)
+	}
+
+	r, err := grove.loadRecent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := r["feature/x"]; ok || len(r) != 1 {
+		t.Errorf("recent = %v, want only main", r)
+	}
+}
@@ -0,0 +1,73 @@
+package grove
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// recent maps each branch to when its worktree was last checked out.
+type recent map[string]time.Time
+
+func (grove *Grove) recentPath() string {
+	return filepath.Join(grove.GrovePath, RecentFileName)
+}
+
+func (grove *Grove) loadRecent() (recent, error) {
+	data, err := os.ReadFile(grove.recentPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return recent{}, nil
+		}
+
+		return nil, err
+	}
+
+	r := recent{}
+	err = yaml.Unmarshal(data, &r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", RecentFileName, err)
+	}
+
+	return r, nil
+}
+
+func (grove *Grove) saveRecent(r recent) error {
+	marshaled, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(grove.recentPath(), marshaled, 0644)
+}
+
+// touchRecent records that the branch's worktree was just checked out.
+func (grove *Grove) touchRecent(branch string) error {
+	r, err := grove.loadRecent()
+	if err != nil {
+		return err
+	}
+
+	r[branch] = time.Now()
+
+	return grove.saveRecent(r)
+}
+
+// forgetRecent removes the branch from the recently checked out worktrees.
+func (grove *Grove) forgetRecent(branch string) error {
+	r, err := grove.loadRecent()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := r[branch]; !ok {
+		return nil
+	}
+
+	delete(r, branch)
+
+	return grove.saveRecent(r)
+}
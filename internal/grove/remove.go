@@ -0,0 +1,147 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+var (
+	ErrUncommittedChanges = errors.New("worktree has uncommitted changes")
+	ErrUnpushedCommits    = errors.New("worktree has unpushed commits")
+)
+
+type RemoveArgs struct {
+	Branch       string // Supports aliases j/fm-3311
+	Force        bool   // Remove the worktree even if it has uncommitted or unpushed changes
+	DeleteBranch bool   // Delete the local branch once the worktree is removed
+	// Merged is set when the branch is known to be merged or deleted on the
+	// remote, in which case only uncommitted changes prevent the removal and
+	// the branch is deleted even if git doesn't consider it merged
+	Merged bool
+}
+
+func (grove *Grove) Remove(ctx context.Context, arg RemoveArgs) (*git.WorkTree, error) {
+	return util.InDirectory(grove.RepositoryPath, func() (*git.WorkTree, error) {
+		branch, err := grove.resolveWorkTreeBranch(ctx, arg.Branch)
+		if err != nil {
+			return nil, err
+		}
+
+		util.LogInfo(ctx, "removing", slog.String("branch", branch))
+
+		wt, err := git.FindWorkTree(ctx, branch)
+		if err != nil {
+			return nil, fmt.Errorf("error finding worktree for %s: %w", branch, err)
+		}
+
+		if !arg.Force {
+			err = util.InDirectoryNoResult(wt.Path, func() error {
+				if arg.Merged {
+					return ensureNoUncommittedChanges(ctx)
+				}
+
+				return ensureWorkTreeIsClean(ctx)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w, use --force to remove it anyway", err)
+			}
+		}
+
+		data, err := grove.templateData(ctx, branch, wt.Path, false)
+		if err != nil {
+			return nil, err
+		}
+
+		err = util.InDirectoryNoResult(wt.Path, func() error {
+			return grove.executeHooks(ctx, config.HookEventBeforeRemove, data)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = git.RemoveWorkTree(ctx, wt.Path, arg.Force)
+		if err != nil {
+			return nil, err
+		}
+
+		err = util.RemoveEmptyParents(wt.Path, grove.WorkTreesPath)
+		if err != nil {
+			slog.WarnContext(ctx, "error cleaning up empty directories", slog.String("path", wt.Path), slog.String("error", err.Error()))
+		}
+
+		if arg.DeleteBranch {
+			util.LogInfo(ctx, "deleting branch", slog.String("branch", branch))
+
+			err = git.DeleteBranch(ctx, branch, arg.Force || arg.Merged)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = grove.executeHooks(ctx, config.HookEventAfterRemove, data)
+		if err != nil {
+			return nil, err
+		}
+
+		err = grove.releasePort(branch)
+		if err != nil {
+			return nil, err
+		}
+
+		err = grove.forgetSeeded(branch)
+		if err != nil {
+			return nil, err
+		}
+
+		err = grove.forgetRecent(branch)
+		if err != nil {
+			return nil, err
+		}
+
+		util.LogInfo(ctx, "removed worktree", slog.String("path", wt.Path))
+
+		return wt, nil
+	})
+}
+
+// ensureWorkTreeIsClean returns an error if the worktree in the current
+// working directory has uncommitted changes or commits that have not been
+// pushed to a remote.
+func ensureWorkTreeIsClean(ctx context.Context) error {
+	err := ensureNoUncommittedChanges(ctx)
+	if err != nil {
+		return err
+	}
+
+	unpushed, err := git.CountUnpushedCommits(ctx)
+	if err != nil {
+		return err
+	}
+
+	if unpushed > 0 {
+		return fmt.Errorf("%w (%d)", ErrUnpushedCommits, unpushed)
+	}
+
+	return nil
+}
+
+// ensureNoUncommittedChanges returns an error if the worktree in the current
+// working directory has uncommitted changes.
+func ensureNoUncommittedChanges(ctx context.Context) error {
+	dirty, err := git.HasUncommittedChanges(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return ErrUncommittedChanges
+	}
+
+	return nil
+}
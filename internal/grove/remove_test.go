@@ -0,0 +1,98 @@
+package grove
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    RemoveArgs
+		prepare func(t *testing.T, wtPath string)
+		err     error
+		removed bool
+		deleted bool
+	}{
+		{
+			name:    "clean",
+			args:    RemoveArgs{Branch: "feature/x"},
+			removed: true,
+		},
+		{
+			name:    "delete branch",
+			args:    RemoveArgs{Branch: "feature/x", DeleteBranch: true},
+			removed: true,
+			deleted: true,
+		},
+		{
+			name: "uncommitted changes",
+			args: RemoveArgs{Branch: "feature/x"},
+			prepare: func(t *testing.T, wtPath string) {
+				if err := os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("x"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			err: ErrUncommittedChanges,
+		},
+		{
+			name: "unpushed commits",
+			args: RemoveArgs{Branch: "feature/x"},
+			prepare: func(t *testing.T, wtPath string) {
+				runGit(t, wtPath, "commit", "-q", "--allow-empty", "-m", "unpushed")
+			},
+			err: ErrUnpushedCommits,
+		},
+		{
+			name: "forced",
+			args: RemoveArgs{Branch: "feature/x", Force: true},
+			prepare: func(t *testing.T, wtPath string) {
+				if err := os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("x"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			removed: true,
+		},
+		{
+			name: "not found",
+			args: RemoveArgs{Branch: "feature/y"},
+			err:  git.ErrWorkTreeNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			grove, wtPath := newTestRepo(t)
+			if tc.prepare != nil {
+				tc.prepare(t, wtPath)
+			}
+
+			_, err := grove.Remove(context.Background(), tc.args)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Remove() error = %v, want %v", err, tc.err)
+			}
+
+			_, statErr := os.Stat(wtPath)
+			if removed := os.IsNotExist(statErr); removed != tc.removed {
+				t.Errorf("worktree removed = %v, want %v", removed, tc.removed)
+			}
+
+			// The now empty worktree directories are cleaned up
+			if _, err := os.Stat(filepath.Join(grove.WorkTreesPath, "feature")); tc.removed && !os.IsNotExist(err) {
+				t.Errorf("empty parent directory wasn't removed")
+			}
+
+			cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/feature/x")
+			cmd.Dir = grove.RepositoryPath
+			if deleted := cmd.Run() != nil; deleted != tc.deleted {
+				t.Errorf("branch deleted = %v, want %v", deleted, tc.deleted)
+			}
+		})
+	}
+}
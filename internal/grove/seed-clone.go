@@ -0,0 +1,59 @@
+package grove
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+// cloneFromMainWorkTree clones the directories of the main worktree that
+// match the `seed.clone` globs, typically dependencies such as
+// `node_modules`, into the worktree. Files are cloned copy-on-write when the
+// file system supports it, so they take no extra space until modified.
+func (grove *Grove) cloneFromMainWorkTree(ctx context.Context, wt *git.WorkTree) error {
+	clone := grove.Config.Seed.Clone
+	if len(clone.Paths) == 0 {
+		return nil
+	}
+
+	main, err := mainWorkTree(ctx, wt)
+	if err != nil || main == "" {
+		return err
+	}
+
+	cloner := &util.Cloner{Fallback: clone.Fallback}
+
+	var stats util.CloneStats
+	err = grove.walkMatches(main, clone.Paths, func(src string, rel string, d fs.DirEntry) error {
+		// Globs match directories, files are cloned along with them
+		if !d.IsDir() {
+			return nil
+		}
+
+		slog.DebugContext(ctx, "cloning directory", slog.String("path", rel))
+
+		s, err := cloner.CloneTree(src, filepath.Join(wt.Path, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+
+		stats.Add(s)
+
+		return fs.SkipDir
+	})
+	if err != nil {
+		return err
+	}
+
+	util.LogInfo(ctx, "cloned directories from main worktree",
+		slog.Int("files", stats.Files),
+		slog.String("size", util.FormatBytes(stats.Bytes)),
+		slog.String("saved", util.FormatBytes(stats.Saved)),
+	)
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package grove
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+	"github.com/samber/lo"
+)
+
+// seedFromMainWorkTree copies the untracked and ignored files of the main
+// worktree that match the `seed.from-main` globs into the worktree. Tracked
+// files are skipped as the worktree has its own version of them, as are
+// files that exist already, e.g. because they were seeded.
+func (grove *Grove) seedFromMainWorkTree(ctx context.Context, wt *git.WorkTree) error {
+	globs := grove.Config.Seed.FromMain
+	if len(globs) == 0 {
+		return nil
+	}
+
+	main, err := mainWorkTree(ctx, wt)
+	if err != nil || main == "" {
+		return err
+	}
+
+	slog.DebugContext(ctx, "seeding worktree from main worktree", slog.String("workTreePath", wt.Path), slog.String("mainWorkTreePath", main))
+
+	tracked, err := util.InDirectory(main, func() ([]string, error) {
+		return git.ListTrackedFiles(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	isTracked := lo.SliceToMap(tracked, func(path string) (string, bool) {
+		return path, true
+	})
+
+	copied := 0
+	err = grove.walkMatches(main, globs, func(src string, rel string, d fs.DirEntry) error {
+		if d.IsDir() || isTracked[rel] {
+			return nil
+		}
+
+		dest := filepath.Join(wt.Path, filepath.FromSlash(rel))
+		if _, err := os.Lstat(dest); err == nil {
+			slog.DebugContext(ctx, "skipping existing file", slog.String("path", rel))
+			return nil
+		}
+
+		err := os.MkdirAll(filepath.Dir(dest), 0755)
+		if err != nil {
+			return err
+		}
+
+		_, err = copySeed(src, dest, false, nil)
+		if err != nil {
+			return err
+		}
+
+		copied++
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	util.LogInfo(ctx, "seeded worktree from main worktree", slog.Int("files", copied))
+
+	return nil
+}
+
+// mainWorkTree returns the path of the main worktree, empty if there is none
+// other than wt.
+func mainWorkTree(ctx context.Context, wt *git.WorkTree) (string, error) {
+	wts, err := git.ListWorkTrees(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// The main worktree is always listed first
+	if len(wts) == 0 || wts[0].Bare || wts[0].Path == wt.Path {
+		slog.DebugContext(ctx, "no main worktree to seed from")
+		return "", nil
+	}
+
+	return wts[0].Path, nil
+}
+
+// walkMatches walks the worktree at root and calls fn for every file and
+// directory that matches one of the globs or is in a directory that does.
+// Directories that can't contain matches aren't walked. fn receives the
+// slash-separated path relative to root, and may return fs.SkipDir to skip a
+// matching directory.
+func (grove *Grove) walkMatches(root string, globs []string, fn func(path string, rel string, d fs.DirEntry) error) error {
+	matches := func(rel string) bool {
+		return lo.SomeBy(globs, func(glob string) bool {
+			return util.MatchGlob(glob, rel)
+		})
+	}
+
+	mayContainMatches := func(rel string) bool {
+		return lo.SomeBy(globs, func(glob string) bool {
+			return util.MatchGlobPrefix(glob, rel)
+		})
+	}
+
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		// Skip the repository and worktrees nested in the main worktree
+		if d.IsDir() && (d.Name() == ".git" || path == grove.WorkTreesPath || path == grove.GrovePath) {
+			return fs.SkipDir
+		}
+
+		if matches(rel) || lo.SomeBy(parents(rel), matches) {
+			return fn(path, rel, d)
+		}
+
+		if d.IsDir() && !mayContainMatches(rel) {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+}
+
+// parents returns the parent directories of the slash-separated path.
+func parents(rel string) []string {
+	var dirs []string
+	for dir := filepath.ToSlash(filepath.Dir(rel)); dir != "."; dir = filepath.ToSlash(filepath.Dir(dir)) {
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
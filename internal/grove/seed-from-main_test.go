@@ -0,0 +1,83 @@
+package grove
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+func TestSeedFromMainWorkTree(t *testing.T) {
+	main := t.TempDir()
+	wt := &git.WorkTree{Path: t.TempDir(), Branch: "feature/x"}
+
+	files := []string{
+		".env",
+		".env.local",
+		".idea/workspace.xml",
+		".idea/codeStyles/Project.xml",
+		"web/.env",
+		"src/main.go",
+		"worktrees/other/.env",
+	}
+	for _, name := range files {
+		path := filepath.Join(main, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Seed.FromMain = []string{".env*", ".idea/**"}
+
+	grove := Grove{
+		Config:        cfg,
+		WorkTreesPath: filepath.Join(main, "worktrees"),
+	}
+
+	runner := &fakeRunner{responses: map[string]string{
+		"worktree list --porcelain -z": fmt.Sprintf(
+			"worktree %v\x00HEAD abc\x00branch refs/heads/main\x00\x00worktree %v\x00HEAD def\x00branch refs/heads/feature/x\x00\x00",
+			main, wt.Path,
+		),
+		"ls-files -z --full-name :/": ".idea/codeStyles/Project.xml\x00src/main.go\x00",
+	}}
+	ctx := git.ContextWithRunner(context.Background(), runner)
+
+	// Existing files, e.g. seeded ones, are kept
+	if err := os.WriteFile(filepath.Join(wt.Path, ".env.local"), []byte("seeded"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grove.seedFromMainWorkTree(ctx, wt); err != nil {
+		t.Fatalf("seedFromMainWorkTree() error = %v", err)
+	}
+
+	want := map[string]bool{
+		".env":                         true,
+		".env.local":                   true,
+		".idea/workspace.xml":          true,
+		".idea/codeStyles/Project.xml": false,
+		"web/.env":                     false,
+		"src/main.go":                  false,
+		"worktrees/other/.env":         false,
+	}
+	for name, copied := range want {
+		_, err := os.Stat(filepath.Join(wt.Path, name))
+		if copied != (err == nil) {
+			t.Errorf("%s copied = %v, want %v", name, err == nil, copied)
+		}
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(wt.Path, ".env.local")); string(content) != "seeded" {
+		t.Errorf(".env.local = %q, want the existing file kept", content)
+	}
+}
@@ -0,0 +1,197 @@
+package grove
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SeedStatusInSync means the file matches the seed
+	SeedStatusInSync = "in-sync"
+	// SeedStatusModified means the file was changed in the worktree
+	SeedStatusModified = "modified"
+	// SeedStatusOutdated means the seed changed since the file was seeded
+	SeedStatusOutdated = "outdated"
+	// SeedStatusDiverged means both the file and the seed changed
+	SeedStatusDiverged = "diverged"
+	// SeedStatusMissing means the file was deleted from the worktree
+	SeedStatusMissing = "missing"
+)
+
+// seededFile records a file placed in a worktree by seeding.
+type seededFile struct {
+	Mode config.SeedMode `yaml:"mode"`
+	// Source is the path of the seed file relative to the seed directory
+	Source string `yaml:"source"`
+	// Sum is the checksum of the content written, empty for links
+	Sum string `yaml:"sum,omitempty"`
+}
+
+// seeded maps each branch to the files seeded into its worktree, keyed by
+// their slash-separated path relative to the worktree.
+type seeded map[string]map[string]seededFile
+
+func (grove *Grove) seededPath() string {
+	return filepath.Join(grove.GrovePath, SeededFileName)
+}
+
+func (grove *Grove) loadSeeded() (seeded, error) {
+	data, err := os.ReadFile(grove.seededPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return seeded{}, nil
+		}
+
+		return nil, err
+	}
+
+	s := seeded{}
+	err = yaml.Unmarshal(data, &s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", SeededFileName, err)
+	}
+
+	return s, nil
+}
+
+func (grove *Grove) saveSeeded(s seeded) error {
+	marshaled, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(grove.seededPath(), marshaled, 0644)
+}
+
+// forgetSeeded removes the record of the files seeded into the branch's
+// worktree.
+func (grove *Grove) forgetSeeded(branch string) error {
+	s, err := grove.loadSeeded()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s[branch]; !ok {
+		return nil
+	}
+
+	delete(s, branch)
+
+	return grove.saveSeeded(s)
+}
+
+// SeedFileStatus describes how a seeded file differs from its seed.
+type SeedFileStatus struct {
+	Path   string          `json:"path"`
+	Source string          `json:"source"`
+	Mode   config.SeedMode `json:"mode"`
+	Status string          `json:"status"`
+}
+
+// SeedStatus reports how the files seeded into the branch's worktree, or the
+// worktree in the current working directory when branch is empty, have
+// drifted from the seed.
+func (grove *Grove) SeedStatus(ctx context.Context, branch string) (*git.WorkTree, []SeedFileStatus, error) {
+	wt, err := grove.findWorkTree(ctx, branch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s, err := grove.loadSeeded()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := grove.templateData(ctx, wt.Branch, wt.Path, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := s[wt.Branch]
+	statuses := make([]SeedFileStatus, 0, len(files))
+	for path, file := range files {
+		status, err := grove.seededFileStatus(wt, path, file, data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		statuses = append(statuses, SeedFileStatus{
+			Path:   path,
+			Source: file.Source,
+			Mode:   file.Mode,
+			Status: status,
+		})
+	}
+
+	slices.SortFunc(statuses, func(a, b SeedFileStatus) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return wt, statuses, nil
+}
+
+func (grove *Grove) seededFileStatus(wt *git.WorkTree, path string, file seededFile, data *TemplateData) (string, error) {
+	src := filepath.Join(grove.SeedPath, filepath.FromSlash(file.Source))
+	dest := filepath.Join(wt.Path, filepath.FromSlash(path))
+
+	if _, err := os.Lstat(dest); os.IsNotExist(err) {
+		return SeedStatusMissing, nil
+	}
+
+	if file.Mode == config.SeedModeSymlink || file.Mode == config.SeedModeHardlink {
+		if isSeedLink(src, dest, file.Mode) {
+			return SeedStatusInSync, nil
+		}
+
+		return SeedStatusModified, nil
+	}
+
+	// Copied symlinks aren't checked
+	if file.Sum == "" {
+		return SeedStatusInSync, nil
+	}
+
+	current, err := fileSum(dest)
+	if err != nil {
+		return "", err
+	}
+
+	modified := current != file.Sum
+
+	// A seed file that no longer exists is outdated
+	outdated := true
+	content, err := seedContent(src, filepath.Ext(file.Source) == templateSuffix, data)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if err == nil {
+		defer content.Close()
+
+		sum, err := readerSum(content)
+		if err != nil {
+			return "", err
+		}
+
+		outdated = sum != file.Sum
+	}
+
+	switch {
+	case modified && outdated:
+		return SeedStatusDiverged, nil
+	case modified:
+		return SeedStatusModified, nil
+	case outdated:
+		return SeedStatusOutdated, nil
+	default:
+		return SeedStatusInSync, nil
+	}
+}
@@ -0,0 +1,281 @@
+package grove
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+// templateSuffix marks seed files that are rendered as Go templates.
+const templateSuffix = ".tmpl"
+
+// seedWorkTree places the files of the seed directory in the worktree
+// according to their configured mode and records what was seeded. Seed files
+// ending in `.tmpl` are rendered with data and written without the suffix,
+// e.g. `.env.tmpl` is written to `.env`. Templates can't be linked, so they
+// are copied on every checkout unless they're copied once.
+func (grove *Grove) seedWorkTree(ctx context.Context, wt *git.WorkTree, data *TemplateData) error {
+	slog.DebugContext(ctx, "seeding worktree", slog.String("workTreePath", wt.Path), slog.String("seedDirectory", grove.SeedPath))
+
+	s, err := grove.loadSeeded()
+	if err != nil {
+		return err
+	}
+
+	files := s[data.Branch]
+	if files == nil {
+		files = map[string]seededFile{}
+	}
+
+	err = filepath.WalkDir(grove.SeedPath, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(grove.SeedPath, src)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		target := rel
+		isTemplate := d.Type().IsRegular() && strings.HasSuffix(rel, templateSuffix)
+		if isTemplate {
+			target = strings.TrimSuffix(rel, templateSuffix)
+		}
+
+		mode := grove.Config.Seed.ModeFor(target)
+		dest := filepath.Join(wt.Path, filepath.FromSlash(target))
+
+		if d.IsDir() {
+			if mode != config.SeedModeSymlink {
+				return os.MkdirAll(dest, 0755)
+			}
+
+			linked, err := linkSeed(ctx, src, dest, mode)
+			if err != nil {
+				return err
+			}
+
+			if linked {
+				files[target] = seededFile{Mode: mode, Source: rel}
+			}
+
+			return fs.SkipDir
+		}
+
+		if isTemplate && (mode == config.SeedModeSymlink || mode == config.SeedModeHardlink) {
+			mode = config.SeedModeAlwaysCopy
+		}
+
+		seeded, sum, err := seedFile(ctx, src, dest, mode, isTemplate, data)
+		if err != nil {
+			return err
+		}
+
+		if seeded {
+			files[target] = seededFile{Mode: mode, Source: rel, Sum: sum}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s[data.Branch] = files
+
+	return grove.saveSeeded(s)
+}
+
+// seedFile places the seed file at src in the worktree at dest according to
+// mode. It returns whether the file was seeded, and the checksum of the
+// content written when it was copied.
+func seedFile(ctx context.Context, src string, dest string, mode config.SeedMode, isTemplate bool, data *TemplateData) (bool, string, error) {
+	err := os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return false, "", err
+	}
+
+	switch mode {
+	case config.SeedModeSymlink, config.SeedModeHardlink:
+		linked, err := linkSeed(ctx, src, dest, mode)
+		return linked, "", err
+	case config.SeedModeCopyOnce:
+		if _, err := os.Lstat(dest); err == nil {
+			return false, "", nil
+		}
+	}
+
+	sum, err := copySeed(src, dest, isTemplate, data)
+	if err != nil {
+		return false, "", err
+	}
+
+	return true, sum, nil
+}
+
+// copySeed writes the content of the seed file at src to dest, rendering it
+// with data if it's a template, and returns the checksum of the content. The
+// file is replaced rather than written to, so a link to the seed left at dest
+// by another mode doesn't modify the seed.
+func copySeed(src string, dest string, isTemplate bool, data *TemplateData) (string, error) {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return "", err
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return "", err
+		}
+
+		err = os.RemoveAll(dest)
+		if err != nil {
+			return "", err
+		}
+
+		return "", os.Symlink(target, dest)
+	}
+
+	content, err := seedContent(src, isTemplate, data)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".grove-seed-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), content)
+	if err != nil {
+		tmp.Close()
+		return "", err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Chmod(tmp.Name(), info.Mode().Perm())
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(tmp.Name(), dest)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// seedContent opens the content of the seed file at src, rendering it with
+// data if it's a template.
+func seedContent(src string, isTemplate bool, data *TemplateData) (io.ReadCloser, error) {
+	if !isTemplate {
+		return os.Open(src)
+	}
+
+	text, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := util.RenderTemplate(filepath.Base(src), string(text), data)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering seed file %s: %w", src, err)
+	}
+
+	return io.NopCloser(strings.NewReader(rendered)), nil
+}
+
+// linkSeed links dest to the seed at src. It returns false without linking
+// if something other than the link already exists at dest.
+func linkSeed(ctx context.Context, src string, dest string, mode config.SeedMode) (bool, error) {
+	if isSeedLink(src, dest, mode) {
+		return true, nil
+	}
+
+	if _, err := os.Lstat(dest); err == nil {
+		slog.WarnContext(ctx, "not replacing existing file with a link to the seed, remove it to link it", slog.String("path", dest), slog.String("mode", string(mode)))
+		return false, nil
+	}
+
+	if mode == config.SeedModeSymlink {
+		return true, os.Symlink(src, dest)
+	}
+
+	err := os.Link(src, dest)
+	if err != nil {
+		return false, fmt.Errorf("error hard linking seed file %s, the seed and worktree must be on the same file system: %w", src, err)
+	}
+
+	return true, nil
+}
+
+// isSeedLink reports whether dest is linked to src according to mode.
+func isSeedLink(src string, dest string, mode config.SeedMode) bool {
+	switch mode {
+	case config.SeedModeSymlink:
+		target, err := os.Readlink(dest)
+		return err == nil && target == src
+	case config.SeedModeHardlink:
+		srcInfo, err := os.Stat(src)
+		if err != nil {
+			return false
+		}
+
+		destInfo, err := os.Lstat(dest)
+		if err != nil {
+			return false
+		}
+
+		return os.SameFile(srcInfo, destInfo)
+	default:
+		return false
+	}
+}
+
+// fileSum returns the checksum of the file's content.
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return readerSum(f)
+}
+
+// readerSum returns the checksum of the content read from r.
+func readerSum(r io.Reader) (string, error) {
+	hash := sha256.New()
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,232 @@
+package grove
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+func TestSeedWorkTree(t *testing.T) {
+	seed := t.TempDir()
+	wt := &git.WorkTree{Path: t.TempDir()}
+
+	files := map[string]string{
+		"README.md":          "{{.Branch}} is copied as is",
+		".env.tmpl":          "DATABASE_NAME=app_{{.Slug}}\nPORT={{.Port}}\n",
+		"config/ticket.tmpl": "{{.TicketID}}",
+	}
+	for name, content := range files {
+		path := filepath.Join(seed, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	grove := Grove{SeedPath: seed, GrovePath: t.TempDir(), Config: config.DefaultConfig()}
+	data := &TemplateData{Branch: "feature/FM-12-login", Slug: "feature-fm-12-login", Port: 3001, TicketID: "FM-12"}
+
+	if err := grove.seedWorkTree(context.Background(), wt, data); err != nil {
+		t.Fatalf("seedWorkTree() error = %v", err)
+	}
+
+	want := map[string]string{
+		"README.md":     "{{.Branch}} is copied as is",
+		".env":          "DATABASE_NAME=app_feature-fm-12-login\nPORT=3001\n",
+		"config/ticket": "FM-12",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(wt.Path, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+
+	for _, name := range []string{".env.tmpl", "config/ticket.tmpl"} {
+		if _, err := os.Stat(filepath.Join(wt.Path, name)); !os.IsNotExist(err) {
+			t.Errorf("template %s was copied into the worktree", name)
+		}
+	}
+}
+
+func TestSeedWorkTreeInvalidTemplate(t *testing.T) {
+	seed := t.TempDir()
+	if err := os.WriteFile(filepath.Join(seed, ".env.tmpl"), []byte("{{.Missing}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	grove := Grove{SeedPath: seed, GrovePath: t.TempDir(), Config: config.DefaultConfig()}
+	if err := grove.seedWorkTree(context.Background(), &git.WorkTree{Path: t.TempDir()}, &TemplateData{}); err == nil {
+		t.Error("seedWorkTree() error = nil, want an error for the missing field")
+	}
+}
+
+func TestSeedWorkTreeModes(t *testing.T) {
+	seed := t.TempDir()
+	wt := &git.WorkTree{Path: t.TempDir(), Branch: "feature/x"}
+
+	write := func(path string, content string) {
+		t.Helper()
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	read := func(path string) string {
+		t.Helper()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(data)
+	}
+
+	write(filepath.Join(seed, "always"), "seed")
+	write(filepath.Join(seed, ".env.local"), "seed")
+	write(filepath.Join(seed, "cache", "data"), "seed")
+	write(filepath.Join(seed, "secret"), "seed")
+
+	cfg := config.DefaultConfig()
+	cfg.Seed.Files = []config.SeedFile{
+		{Path: ".env.*", Mode: config.SeedModeCopyOnce},
+		{Path: "cache", Mode: config.SeedModeSymlink},
+		{Path: "secret", Mode: config.SeedModeHardlink},
+	}
+
+	grove := Grove{SeedPath: seed, GrovePath: t.TempDir(), Config: cfg}
+	data := &TemplateData{Branch: wt.Branch}
+
+	if err := grove.seedWorkTree(context.Background(), wt, data); err != nil {
+		t.Fatalf("seedWorkTree() error = %v", err)
+	}
+
+	write(filepath.Join(wt.Path, "always"), "edited")
+	write(filepath.Join(wt.Path, ".env.local"), "edited")
+
+	if err := grove.seedWorkTree(context.Background(), wt, data); err != nil {
+		t.Fatalf("seedWorkTree() error = %v", err)
+	}
+
+	if got := read(filepath.Join(wt.Path, "always")); got != "seed" {
+		t.Errorf("always-copy file = %q, want it overwritten", got)
+	}
+
+	if got := read(filepath.Join(wt.Path, ".env.local")); got != "edited" {
+		t.Errorf("copy-once file = %q, want the edit kept", got)
+	}
+
+	if target, err := os.Readlink(filepath.Join(wt.Path, "cache")); err != nil || target != filepath.Join(seed, "cache") {
+		t.Errorf("symlinked directory points to %q (%v), want the seed", target, err)
+	}
+
+	write(filepath.Join(seed, "secret"), "shared")
+	if got := read(filepath.Join(wt.Path, "secret")); got != "shared" {
+		t.Errorf("hard linked file = %q, want the seed's content", got)
+	}
+}
+
+func TestSeededFileStatus(t *testing.T) {
+	seed := t.TempDir()
+	wt := &git.WorkTree{Path: t.TempDir(), Branch: "feature/x"}
+
+	for _, name := range []string{"in-sync", "modified", "outdated", "diverged", "missing"} {
+		if err := os.WriteFile(filepath.Join(seed, name), []byte("seed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	grove := Grove{SeedPath: seed, GrovePath: t.TempDir(), Config: config.DefaultConfig()}
+	data := &TemplateData{Branch: wt.Branch}
+
+	if err := grove.seedWorkTree(context.Background(), wt, data); err != nil {
+		t.Fatalf("seedWorkTree() error = %v", err)
+	}
+
+	for _, name := range []string{"modified", "diverged"} {
+		if err := os.WriteFile(filepath.Join(wt.Path, name), []byte("edited"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"outdated", "diverged"} {
+		if err := os.WriteFile(filepath.Join(seed, name), []byte("updated"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(wt.Path, "missing")); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := grove.loadSeeded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, file := range s[wt.Branch] {
+		status, err := grove.seededFileStatus(wt, path, file, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if status != path {
+			t.Errorf("status of %s = %s", path, status)
+		}
+	}
+}
+
+func TestSeedStatus(t *testing.T) {
+	grove, wtPath := newTestRepo(t)
+	wt := &git.WorkTree{Path: wtPath, Branch: "feature/x"}
+
+	if err := os.WriteFile(filepath.Join(grove.SeedPath, ".env.tmpl"), []byte("PORT={{.Port}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grove.savePorts(ports{"feature/x": 3005}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grove.seedWorkTree(context.Background(), wt, &TemplateData{Branch: wt.Branch, Port: 3005}); err != nil {
+		t.Fatalf("seedWorkTree() error = %v", err)
+	}
+
+	// The template is rendered with the worktree's allocated port
+	_, statuses, err := grove.SeedStatus(context.Background(), wt.Branch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 1 || statuses[0].Status != SeedStatusInSync {
+		t.Errorf("SeedStatus() = %v, want .env in sync", statuses)
+	}
+
+	// Reporting the status is read-only and never allocates a port
+	if err := grove.releasePort(wt.Branch); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := grove.SeedStatus(context.Background(), wt.Branch); err != nil {
+		t.Fatal(err)
+	}
+
+	assertPorts(t, grove, ports{})
+}
@@ -0,0 +1,199 @@
+package grove
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+// WorkTreeSummary summarizes the state of a worktree to help tell which
+// worktrees are stale.
+type WorkTreeSummary struct {
+	Path     string `json:"path"`
+	Branch   string `json:"branch"`
+	Detached bool   `json:"detached"`
+	Prunable bool   `json:"prunable"`
+	// DirtyFiles is the number of files with uncommitted changes
+	DirtyFiles int `json:"dirtyFiles"`
+	// Stashes is the number of stash entries created on the branch
+	Stashes int `json:"stashes"`
+	// Unpushed is the number of commits that don't exist on any remote
+	Unpushed int    `json:"unpushed"`
+	Upstream string `json:"upstream,omitempty"`
+	// UpstreamGone is set when the branch's upstream was deleted on the
+	// remote, usually because it was merged
+	UpstreamGone bool `json:"upstreamGone"`
+	// LastTouched is the most recent of the last commit and the last
+	// modification of a changed file
+	LastTouched time.Time `json:"lastTouched"`
+	// DiskUsage is the size of the files in the worktree in bytes
+	DiskUsage int64 `json:"diskUsage"`
+	// Error explains why the worktree couldn't be summarized, e.g. because
+	// its branch has no commits yet, in which case the summary is incomplete
+	Error string `json:"error,omitempty"`
+}
+
+// Status summarizes every worktree in the repository. Worktrees are
+// inspected concurrently. A worktree that can't be summarized doesn't fail
+// the others, the error is recorded in its summary instead.
+func (grove *Grove) Status(ctx context.Context) ([]WorkTreeSummary, error) {
+	repoCtx := git.ContextWithDir(ctx, grove.RepositoryPath)
+
+	wts, err := git.ListWorkTrees(repoCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	stashes, err := git.CountStashes(repoCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	upstreams, err := git.ListUpstreams(repoCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, runtime.NumCPU())
+		summaries = make([]*WorkTreeSummary, len(wts))
+	)
+
+	for i, wt := range wts {
+		if wt.Bare {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			summary := &WorkTreeSummary{
+				Path:         wt.Path,
+				Branch:       wt.Branch,
+				Detached:     wt.Detached,
+				Prunable:     wt.Prunable,
+				Stashes:      stashes[wt.Branch],
+				Upstream:     upstreams[wt.Branch].Name,
+				UpstreamGone: upstreams[wt.Branch].Gone,
+			}
+
+			err := grove.summarizeWorkTree(ctx, wt, summary)
+			if err != nil {
+				slog.DebugContext(ctx, "error summarizing worktree", slog.String("path", wt.Path), slog.String("error", err.Error()))
+				summary.Error = err.Error()
+			}
+
+			summaries[i] = summary
+		}()
+	}
+
+	wg.Wait()
+
+	result := make([]WorkTreeSummary, 0, len(wts))
+	for _, summary := range summaries {
+		if summary != nil {
+			result = append(result, *summary)
+		}
+	}
+
+	return result, nil
+}
+
+// summarizeWorkTree fills in the parts of the summary that are specific to
+// the worktree's directory.
+func (grove *Grove) summarizeWorkTree(ctx context.Context, wt git.WorkTree, summary *WorkTreeSummary) error {
+	slog.DebugContext(ctx, "summarizing worktree", slog.String("path", wt.Path))
+
+	// The directory of a prunable worktree no longer exists
+	if wt.Prunable {
+		return nil
+	}
+
+	ctx = git.ContextWithDir(ctx, wt.Path)
+
+	changed, err := git.ListChangedFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary.DirtyFiles = len(changed)
+
+	commit, err := git.GetCommit(ctx, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	summary.LastTouched = commit.Date
+	for _, file := range changed {
+		info, err := os.Lstat(filepath.Join(wt.Path, filepath.FromSlash(file)))
+		if err != nil {
+			// Deleted files
+			continue
+		}
+
+		if info.ModTime().After(summary.LastTouched) {
+			summary.LastTouched = info.ModTime()
+		}
+	}
+
+	summary.Unpushed, err = git.CountUnpushedCommits(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary.DiskUsage, err = grove.diskUsage(wt.Path)
+
+	return err
+}
+
+// diskUsage returns the total size of the files in the directory, excluding
+// the worktrees nested in it and, in the main worktree, the repository's
+// `.git` and `.grove` directories.
+func (grove *Grove) diskUsage(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files may be removed while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if d.IsDir() && path != dir && (path == grove.WorkTreesPath || path == grove.GrovePath || path == filepath.Join(dir, ".git")) {
+			return fs.SkipDir
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,84 @@
+package grove
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	grove, _ := newTestRepo(t)
+	repo := grove.RepositoryPath
+
+	// A worktree whose branch has no commits yet has no HEAD commit
+	orphan := filepath.Join(repo, "worktrees", "orphan")
+	runGit(t, repo, "worktree", "add", "-q", "--detach", orphan)
+	runGit(t, orphan, "checkout", "-q", "--orphan", "orphan")
+
+	summaries, err := grove.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := map[string]string{}
+	for _, s := range summaries {
+		errs[s.Branch] = s.Error
+	}
+
+	if len(errs) != 3 {
+		t.Fatalf("Status() = %v, want main, feature/x and orphan", summaries)
+	}
+
+	if errs["orphan"] == "" {
+		t.Error("Status() didn't record the orphan worktree's error")
+	}
+
+	if errs["main"] != "" || errs["feature/x"] != "" {
+		t.Errorf("Status() errors = %v, want only orphan to fail", errs)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	grove, wtPath := newTestRepo(t)
+	repo := grove.RepositoryPath
+	grove.GrovePath = filepath.Join(repo, ".grove")
+
+	files := map[string]int{
+		filepath.Join(repo, "main.go"):               100,
+		filepath.Join(repo, "web", "index.html"):     20,
+		filepath.Join(grove.GrovePath, "ports.yaml"): 1000,
+		filepath.Join(wtPath, "big"):                 1000,
+	}
+
+	for path, size := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The repository's .git and .grove directories and the nested
+	// worktrees don't count towards the main worktree
+	size, err := grove.diskUsage(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size != 120 {
+		t.Errorf("diskUsage(main) = %d, want 120", size)
+	}
+
+	size, err = grove.diskUsage(wtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The worktree's .git is a small file pointing at the repository
+	if size < 1000 || size > 1100 {
+		t.Errorf("diskUsage(worktree) = %d, want about 1000", size)
+	}
+}
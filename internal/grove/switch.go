@@ -0,0 +1,99 @@
+package grove
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/git"
+)
+
+// SwitchCandidate is a worktree or branch that can be checked out.
+type SwitchCandidate struct {
+	Branch string
+	// Path is the path of the branch's worktree, empty if it doesn't have one
+	Path string
+	// LastUsed is when the worktree was last checked out with grove, zero if
+	// it never was
+	LastUsed time.Time
+	// LastCommit is the date of the branch's latest commit
+	LastCommit time.Time
+}
+
+// SwitchCandidates returns the worktrees and branches that can be checked
+// out, most recently used first. Worktrees come before branches without one,
+// which are ordered by their latest commit.
+func (grove *Grove) SwitchCandidates(ctx context.Context) ([]SwitchCandidate, error) {
+	ctx = git.ContextWithDir(ctx, grove.RepositoryPath)
+
+	wts, err := git.ListWorkTrees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := git.ListBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dates, err := git.ListBranchDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := grove.loadRecent()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []SwitchCandidate
+	seen := map[string]bool{}
+	for _, wt := range wts {
+		if wt.Bare || wt.Detached || wt.Prunable {
+			continue
+		}
+
+		seen[wt.Branch] = true
+		candidates = append(candidates, SwitchCandidate{
+			Branch:     wt.Branch,
+			Path:       wt.Path,
+			LastUsed:   used[wt.Branch],
+			LastCommit: dates[wt.Branch],
+		})
+	}
+
+	for _, branch := range branches {
+		if seen[branch] {
+			continue
+		}
+
+		seen[branch] = true
+		candidates = append(candidates, SwitchCandidate{
+			Branch:     branch,
+			LastCommit: dates[branch],
+		})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b SwitchCandidate) int {
+		if c := b.LastUsed.Compare(a.LastUsed); c != 0 {
+			return c
+		}
+
+		if hasA, hasB := a.Path != "", b.Path != ""; hasA != hasB {
+			if hasA {
+				return -1
+			}
+
+			return 1
+		}
+
+		if c := b.LastCommit.Compare(a.LastCommit); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Branch, b.Branch)
+	})
+
+	return candidates, nil
+}
@@ -0,0 +1,91 @@
+package grove
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+// TemplateData describes a worktree and the event being handled. It's
+// available to hooks as Go template data, e.g. `{{.Port}}`, and as
+// environment variables.
+type TemplateData struct {
+	Event      config.HookEvent
+	Branch     string
+	Slug       string
+	Path       string
+	RepoRoot   string
+	BaseBranch string
+	IsNew      bool
+	Port       int
+	// TicketID is the ticket ID found in the branch name, e.g. `FM-3311` in
+	// `feature/FM-3311-login`, empty if there is none
+	TicketID string
+}
+
+// checkoutEvents are the events whether the worktree is new applies to.
+var checkoutEvents = []config.HookEvent{
+	config.HookEventBeforeCheckout,
+	config.HookEventAfterCreate,
+	config.HookEventAfterSwitch,
+	config.HookEventAfterCheckout,
+}
+
+// Env returns the data as environment variables in the form KEY=value.
+// GROVE_IS_NEW is only set for checkout events and GROVE_PORT only when the
+// worktree has a port, e.g. not for after-prune.
+func (d TemplateData) Env() []string {
+	env := []string{
+		"GROVE_EVENT=" + string(d.Event),
+		"GROVE_BRANCH=" + d.Branch,
+		"GROVE_SLUG=" + d.Slug,
+		"GROVE_WORKTREE_PATH=" + d.Path,
+		"GROVE_REPO_ROOT=" + d.RepoRoot,
+		"GROVE_BASE_BRANCH=" + d.BaseBranch,
+		"GROVE_TICKET_ID=" + d.TicketID,
+	}
+
+	if slices.Contains(checkoutEvents, d.Event) {
+		env = append(env, "GROVE_IS_NEW="+strconv.FormatBool(d.IsNew))
+	}
+
+	if d.Port != 0 {
+		env = append(env, "GROVE_PORT="+strconv.Itoa(d.Port))
+	}
+
+	return env
+}
+
+// templateData returns the template data of the branch's worktree located
+// at path. A new worktree is given the port it will be allocated once it
+// exists, an existing one the port it was allocated, if any.
+func (grove *Grove) templateData(ctx context.Context, branch string, path string, isNew bool) (*TemplateData, error) {
+	base, err := grove.baseBranch(ctx, branch)
+	if err != nil {
+		slog.DebugContext(ctx, "unable to determine base branch", slog.String("branch", branch), slog.String("error", err.Error()))
+	}
+
+	port, err := grove.lookupPort(branch)
+	if isNew {
+		port, _, err = grove.choosePort(ctx, branch)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateData{
+		Branch:     branch,
+		Slug:       util.Slugify(branch),
+		Path:       path,
+		RepoRoot:   grove.RepositoryPath,
+		BaseBranch: base,
+		IsNew:      isNew,
+		Port:       port,
+		TicketID:   grove.ticketID(branch),
+	}, nil
+}
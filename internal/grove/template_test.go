@@ -0,0 +1,54 @@
+package grove
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestTemplateDataEnv(t *testing.T) {
+	data := TemplateData{
+		Event:      config.HookEventAfterCreate,
+		Branch:     "feature/FM-3311-login",
+		Slug:       "feature-fm-3311-login",
+		Path:       "/repo/worktrees/feature/FM-3311-login",
+		RepoRoot:   "/repo",
+		BaseBranch: "main",
+		IsNew:      true,
+		Port:       3001,
+		TicketID:   "FM-3311",
+	}
+
+	want := []string{
+		"GROVE_EVENT=after-create",
+		"GROVE_BRANCH=feature/FM-3311-login",
+		"GROVE_SLUG=feature-fm-3311-login",
+		"GROVE_WORKTREE_PATH=/repo/worktrees/feature/FM-3311-login",
+		"GROVE_REPO_ROOT=/repo",
+		"GROVE_BASE_BRANCH=main",
+		"GROVE_TICKET_ID=FM-3311",
+		"GROVE_IS_NEW=true",
+		"GROVE_PORT=3001",
+	}
+
+	if got := data.Env(); !slices.Equal(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+
+	// Empty values are still set, so hooks don't inherit them, but whether
+	// the worktree is new and its port don't apply to every event
+	want = []string{
+		"GROVE_EVENT=after-prune",
+		"GROVE_BRANCH=",
+		"GROVE_SLUG=",
+		"GROVE_WORKTREE_PATH=",
+		"GROVE_REPO_ROOT=/repo",
+		"GROVE_BASE_BRANCH=",
+		"GROVE_TICKET_ID=",
+	}
+
+	if got := (TemplateData{Event: config.HookEventAfterPrune, RepoRoot: "/repo"}).Env(); !slices.Equal(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+package grove
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jacobdrury/grove/internal/config"
+	"github.com/jacobdrury/grove/internal/util"
+)
+
+// ticketPatterns caches the compiled ticket patterns by their expression, as
+// they're matched against every branch during resolution.
+var ticketPatterns sync.Map
+
+// ticketPattern returns the configured ticket pattern, matching ticket IDs
+// at the start of a segment of a branch name, ignoring case if requested.
+func (grove *Grove) ticketPattern(ignoreCase bool) (*regexp.Regexp, error) {
+	br := grove.Config.BranchResolver
+
+	pattern := br.Tickets.Pattern
+	if pattern == "" {
+		pattern = config.DefaultTicketPattern
+	}
+
+	start := "^"
+	if br.BranchDelimiter != "" {
+		start = "(?:^|" + regexp.QuoteMeta(br.BranchDelimiter) + ")"
+	}
+
+	expr := start + "(" + string(pattern) + ")(?:$|[^0-9])"
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	if re, ok := ticketPatterns.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket pattern %q: %v", pattern, err)
+	}
+
+	ticketPatterns.Store(expr, re)
+	return re, nil
+}
+
+// ticketID returns the first ticket ID in the branch name, ignoring case as
+// the branches created for tickets are lowercase, empty if there is none.
+func (grove *Grove) ticketID(branch string) string {
+	return grove.findTicketID(branch, true)
+}
+
+// findTicketID returns the first ticket ID in the name, empty if there is
+// none.
+func (grove *Grove) findTicketID(name string, ignoreCase bool) string {
+	re, err := grove.ticketPattern(ignoreCase)
+	if err != nil {
+		slog.Warn("error matching ticket ID", slog.String("error", err.Error()))
+		return ""
+	}
+
+	match := re.FindStringSubmatch(name)
+	if match == nil {
+		return ""
+	}
+
+	return match[1]
+}
+
+// isTicketID reports whether the whole name is a ticket ID, ignoring case,
+// e.g. `FM-331` or `fm-331`.
+func (grove *Grove) isTicketID(name string) bool {
+	return name != "" && grove.ticketID(name) == name
+}
+
+// isExplicitTicketID reports whether the whole name is a ticket ID as the
+// pattern spells it, e.g. `FM-331` but not `fm-331`, so names like `node-18`
+// or `release-2024` aren't mistaken for tickets.
+func (grove *Grove) isExplicitTicketID(name string) bool {
+	return name != "" && grove.findTicketID(name, false) == name
+}
+
+// newBranchName returns the name of the branch created for a name that
+// doesn't match any branch. A ticket ID becomes the ticket's branch, e.g.
+// `FM-331` becomes `user1/fm-331-fix-login` with the configured prefix and the
+// title of the ticket, shortened to the maximum length of the branch policy.
+// The title is fetched with the title provider unless it's specified. Other
+// names, including lowercase ticket IDs, are only expanded.
+func (grove *Grove) newBranchName(ctx context.Context, val string, title string) (string, error) {
+	tickets := grove.Config.BranchResolver.Tickets
+	delimiter := grove.Config.BranchResolver.BranchDelimiter
+
+	name := grove.expandPrefixAliases(val)
+	prefix, id := splitBranch(name, delimiter)
+	if !grove.isExplicitTicketID(id) {
+		if title != "" {
+			slog.WarnContext(ctx, "ignoring title, the branch isn't named after a ticket ID", slog.String("branch", name))
+		}
+
+		return name, nil
+	}
+
+	if title == "" && tickets.TitleProvider != "" {
+		var err error
+		title, err = grove.ticketTitle(ctx, id)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	slug := util.Slugify(id + " " + title)
+	if prefix == "" {
+		prefix = tickets.Prefix
+	}
+
+	branch := slug
+	if prefix != "" && delimiter != "" {
+		branch = prefix + delimiter + slug
+	}
+
+	// Titles are often longer than the branch policy allows
+	if maxLength := grove.Config.BranchPolicy.MaxLength; maxLength > 0 {
+		branch = shortenBranchName(branch, maxLength, delimiter)
+	}
+
+	return branch, nil
+}
+
+// ticketTitle runs the title provider and returns the first line it prints
+// as the title of the ticket.
+func (grove *Grove) ticketTitle(ctx context.Context, id string) (string, error) {
+	provider := grove.Config.BranchResolver.Tickets.TitleProvider
+
+	data := struct{ TicketID string }{TicketID: id}
+	cmd, err := util.RenderTemplate("title-provider", provider, data)
+	if err != nil {
+		return "", fmt.Errorf("error rendering title provider %s: %v", provider, err)
+	}
+
+	util.LogInfo(ctx, "fetching ticket title", slog.String("ticket", id))
+
+	var out strings.Builder
+	err = util.ExecShellCmd(ctx, util.ShellCmd{
+		Shell:   grove.Config.Hooks.Shell,
+		Mode:    grove.Config.Hooks.ShellMode,
+		Command: cmd,
+		Env:     []string{"GROVE_TICKET_ID=" + id},
+		Stdout:  &out,
+		Stderr:  os.Stderr,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error fetching the title of %s with %s: %v, pass it with --title instead", id, cmd, err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		if title := strings.TrimSpace(scanner.Text()); title != "" {
+			return title, nil
+		}
+	}
+
+	return "", fmt.Errorf("title provider %s printed no title for %s, pass it with --title instead", cmd, id)
+}
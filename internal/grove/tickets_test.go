@@ -0,0 +1,88 @@
+package grove
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestTicketID(t *testing.T) {
+	tests := []struct {
+		pattern config.TicketPattern
+		branch  string
+		want    string
+	}{
+		{branch: "feature/FM-3311-login", want: "FM-3311"},
+		{branch: "u/fm-432-asdf-test", want: "fm-432"},
+		{branch: "ABC-1", want: "ABC-1"},
+		{branch: "feature/login", want: ""},
+		{branch: "release/v1-2", want: ""},
+		{branch: "feature/login-page", want: ""},
+		{pattern: `[A-Z]+-\d+`, branch: "u/v-12-fix", want: "v-12"},
+		{pattern: `\d+`, branch: "u/123-fix", want: "123"},
+		{pattern: `\d+`, branch: "u/fm-123-fix", want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.pattern)+" "+tc.branch, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			if tc.pattern != "" {
+				cfg.BranchResolver.Tickets.Pattern = tc.pattern
+			}
+
+			grove := Grove{Config: cfg}
+			if got := grove.ticketID(tc.branch); got != tc.want {
+				t.Errorf("ticketID(%q) = %q, want %q", tc.branch, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewBranchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		title    string
+		prefix   string
+		provider string
+		length   int
+		want     string
+		wantErr  bool
+	}{
+		{name: "not a ticket", in: "u/login", title: "Ignored", want: "user1/login"},
+		{name: "title", in: "FM-331", title: "Fix the Login!", prefix: "user1", want: "user1/fm-331-fix-the-login"},
+		{name: "typed prefix", in: "u/FM-331", title: "Fix login", prefix: "other", want: "user1/fm-331-fix-login"},
+		{name: "no prefix", in: "FM-331", title: "Fix login", want: "fm-331-fix-login"},
+		{name: "max length", in: "FM-331", title: "Fix the login page", prefix: "user1", length: 20, want: "user1/fm-331-fix-the"},
+		{name: "no title", in: "FM-331", prefix: "user1", want: "user1/fm-331"},
+		{name: "provider", in: "FM-331", prefix: "user1", provider: "echo; echo \"Title of {{.TicketID}} $GROVE_TICKET_ID\"", want: "user1/fm-331-title-of-fm-331-fm-331"},
+		{name: "title beats provider", in: "FM-331", title: "Fix login", provider: "exit 1", want: "fm-331-fix-login"},
+		{name: "provider fails", in: "FM-331", provider: "exit 1", wantErr: true},
+		{name: "provider prints nothing", in: "FM-331", provider: "true", wantErr: true},
+		// Lowercase names are rarely meant as tickets
+		{name: "lowercase", in: "u/node-18", provider: "exit 1", prefix: "other", want: "user1/node-18"},
+		{name: "lowercase ticket", in: "fm-331", title: "Ignored", provider: "exit 1", want: "fm-331"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Hooks.Shell = "/bin/sh"
+			cfg.BranchResolver.BranchPrefixAliases = map[config.BranchPrefixAlias]config.BranchPrefix{"u": "user1"}
+			cfg.BranchResolver.Tickets.Prefix = tc.prefix
+			cfg.BranchResolver.Tickets.TitleProvider = tc.provider
+			cfg.BranchPolicy.MaxLength = tc.length
+
+			grove := Grove{Config: cfg}
+			got, err := grove.newBranchName(context.Background(), tc.in, tc.title)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("newBranchName(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			}
+
+			if got != tc.want {
+				t.Errorf("newBranchName(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
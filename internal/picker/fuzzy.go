@@ -0,0 +1,122 @@
+package picker
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	scoreMatch       = 16
+	scoreConsecutive = 12
+	scoreBoundary    = 8
+	penaltyGap       = 1
+)
+
+// Match reports whether every whitespace separated term of the query matches
+// the text, i.e. the term's characters appear in the text in order, ignoring
+// case. The score ranks how well the query matches, higher is better, and
+// favors consecutive characters and characters at the start of words. The
+// positions are the indices of the matched runes in the text.
+func Match(query string, text string) (int, []int, bool) {
+	original := []rune(text)
+	runes := lower(original)
+
+	total := 0
+	var positions []int
+	for _, term := range strings.Fields(query) {
+		score, matched, ok := matchTerm(lower([]rune(term)), runes, original)
+		if !ok {
+			return 0, nil, false
+		}
+
+		total += score
+		positions = append(positions, matched...)
+	}
+
+	return total, positions, true
+}
+
+// matchTerm returns the best scoring match of the term starting at each
+// occurrence of its first character.
+func matchTerm(term []rune, runes []rune, original []rune) (int, []int, bool) {
+	best, found := 0, false
+	var bestPositions []int
+
+	for start, r := range runes {
+		if r != term[0] {
+			continue
+		}
+
+		positions := make([]int, 0, len(term))
+		positions = append(positions, start)
+		for i := start + 1; i < len(runes) && len(positions) < len(term); i++ {
+			if runes[i] == term[len(positions)] {
+				positions = append(positions, i)
+			}
+		}
+
+		if len(positions) < len(term) {
+			// Later starts can't match either
+			break
+		}
+
+		score := scorePositions(positions, original)
+		if !found || score > best {
+			best, bestPositions, found = score, positions, true
+		}
+	}
+
+	return best, bestPositions, found
+}
+
+func scorePositions(positions []int, original []rune) int {
+	score := 0
+	for i, pos := range positions {
+		score += scoreMatch
+
+		if isBoundary(original, pos) {
+			score += scoreBoundary
+		}
+
+		if i > 0 {
+			if gap := pos - positions[i-1] - 1; gap == 0 {
+				score += scoreConsecutive
+			} else {
+				score -= gap * penaltyGap
+			}
+		}
+	}
+
+	return score
+}
+
+// isBoundary reports whether the rune at pos starts a word, e.g. the f in
+// feature/fm-331 or the L in myLogin.
+func isBoundary(runes []rune, pos int) bool {
+	if pos == 0 {
+		return true
+	}
+
+	prev, cur := runes[pos-1], runes[pos]
+	switch {
+	case strings.ContainsRune("/-_. ", prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return true
+	case !unicode.IsDigit(prev) && unicode.IsDigit(cur):
+		return true
+	default:
+		return false
+	}
+}
+
+// lower lowercases each rune, unlike strings.ToLower it never changes the
+// number of runes, so positions in the result are positions in the original.
+func lower(runes []rune) []rune {
+	lowered := make([]rune, len(runes))
+	for i, r := range runes {
+		lowered[i] = unicode.ToLower(r)
+	}
+
+	return lowered
+}
@@ -0,0 +1,59 @@
+package picker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query     string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{query: "", text: "main", ok: true},
+		{query: "fm331", text: "feature/FM-331-login", ok: true, positions: []int{8, 9, 11, 12, 13}},
+		{query: "login fm", text: "feature/FM-331-login", ok: true, positions: []int{15, 16, 17, 18, 19, 8, 9}},
+		{query: "lgn", text: "feature/login", ok: true, positions: []int{8, 10, 12}},
+		{query: "nigol", text: "feature/login", ok: false},
+		{query: "main x", text: "main", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			_, positions, ok := Match(tc.query, tc.text)
+			if ok != tc.ok {
+				t.Fatalf("Match(%q, %q) ok = %v, want %v", tc.query, tc.text, ok, tc.ok)
+			}
+
+			if !reflect.DeepEqual(positions, tc.positions) {
+				t.Errorf("Match(%q, %q) positions = %v, want %v", tc.query, tc.text, positions, tc.positions)
+			}
+		})
+	}
+}
+
+func TestMatchScore(t *testing.T) {
+	// Each query should match the first text better than the second
+	tests := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		{query: "login", better: "feature/login", worse: "feature/legacy-ordering-in"},
+		{query: "fl", better: "feature/login", worse: "self-help"},
+		{query: "331", better: "fm-331-login", worse: "fm-3-3-1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			better, _, _ := Match(tc.query, tc.better)
+			worse, _, _ := Match(tc.query, tc.worse)
+
+			if better <= worse {
+				t.Errorf("Match(%q, %q) = %d, want more than %d for %q", tc.query, tc.better, better, worse, tc.worse)
+			}
+		})
+	}
+}
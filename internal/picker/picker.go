@@ -0,0 +1,314 @@
+package picker
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+var ErrCanceled = errors.New("canceled")
+
+// Item is an entry that can be picked.
+type Item struct {
+	// Text is matched against the query
+	Text string
+	// Detail is shown dimmed after the text, but isn't matched
+	Detail string
+}
+
+// Picker is an interactive fuzzy finder drawn directly on the terminal with
+// ANSI escape sequences, so it works without any external tools.
+type Picker struct {
+	Prompt string
+	// Items are shown in order until a query is entered, after which they
+	// are ranked by how well they match it
+	Items []Item
+	// Preview returns the text shown below the items for the item at the
+	// index. It's optional.
+	Preview func(i int) string
+}
+
+// Run lets the user pick an item, reading keys from stdin and drawing on
+// stderr, so stdout can be captured. It returns the index of the picked item
+// or ErrCanceled if the user quit without picking one.
+func (p *Picker) Run() (int, error) {
+	in, out := os.Stdin, os.Stderr
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return 0, fmt.Errorf("error configuring terminal: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	// Draw on the alternate screen, which restores the terminal's contents
+	// once the picker exits
+	fmt.Fprint(out, "\x1b[?1049h")
+	defer fmt.Fprint(out, "\x1b[?1049l")
+
+	m := newModel(p.Items)
+	previews := map[int]string{}
+
+	buf := make([]byte, 64)
+	for {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+
+		var preview string
+		if i, ok := m.selectedItem(); ok && p.Preview != nil {
+			if _, ok := previews[i]; !ok {
+				previews[i] = p.Preview(i)
+			}
+
+			preview = previews[i]
+		}
+
+		_, err = out.WriteString(m.render(p.Prompt, preview, width, height))
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+
+		switch m.handle(buf[:n]) {
+		case actionAccept:
+			i, _ := m.selectedItem()
+			return i, nil
+		case actionCancel:
+			return 0, ErrCanceled
+		}
+	}
+}
+
+type action int
+
+const (
+	actionNone action = iota
+	actionAccept
+	actionCancel
+)
+
+// match is an item matching the query.
+type match struct {
+	index     int
+	score     int
+	positions []int
+}
+
+// model is the state of the picker, separate from the terminal.
+type model struct {
+	items    []Item
+	query    []rune
+	matches  []match
+	selected int
+	offset   int
+}
+
+func newModel(items []Item) *model {
+	m := &model{items: items}
+	m.filter()
+
+	return m
+}
+
+// filter ranks the items matching the query. Items that match equally well
+// keep their order.
+func (m *model) filter() {
+	m.matches = m.matches[:0]
+	for i, item := range m.items {
+		score, positions, ok := Match(string(m.query), item.Text)
+		if ok {
+			m.matches = append(m.matches, match{index: i, score: score, positions: positions})
+		}
+	}
+
+	slices.SortStableFunc(m.matches, func(a, b match) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	m.selected = 0
+	m.offset = 0
+}
+
+// selectedItem returns the index of the selected item, if any item matches.
+func (m *model) selectedItem() (int, bool) {
+	if len(m.matches) == 0 {
+		return 0, false
+	}
+
+	return m.matches[m.selected].index, true
+}
+
+func (m *model) move(delta int) {
+	m.selected = max(0, min(len(m.matches)-1, m.selected+delta))
+}
+
+// handle updates the model with the keys read from the terminal.
+func (m *model) handle(input []byte) action {
+	switch {
+	case len(input) == 0:
+		return actionNone
+	case bytes.Equal(input, []byte{0x1b}), // Escape
+		bytes.Equal(input, []byte{0x03}),                      // Ctrl-C
+		bytes.Equal(input, []byte{0x04}) && len(m.query) == 0: // Ctrl-D
+		return actionCancel
+	case bytes.Equal(input, []byte{'\r'}), bytes.Equal(input, []byte{'\n'}):
+		if len(m.matches) == 0 {
+			return actionNone
+		}
+
+		return actionAccept
+	case bytes.Equal(input, []byte("\x1b[A")), bytes.Equal(input, []byte("\x1bOA")), bytes.Equal(input, []byte{0x10}): // Up, Ctrl-P
+		m.move(-1)
+		return actionNone
+	case bytes.Equal(input, []byte("\x1b[B")), bytes.Equal(input, []byte("\x1bOB")), bytes.Equal(input, []byte{0x0e}): // Down, Ctrl-N
+		m.move(1)
+		return actionNone
+	case bytes.Equal(input, []byte("\x1b[5~")): // Page up
+		m.move(-10)
+		return actionNone
+	case bytes.Equal(input, []byte("\x1b[6~")): // Page down
+		m.move(10)
+		return actionNone
+	case bytes.Equal(input, []byte{0x7f}), bytes.Equal(input, []byte{0x08}): // Backspace
+		if len(m.query) > 0 {
+			m.query = m.query[:len(m.query)-1]
+			m.filter()
+		}
+
+		return actionNone
+	case bytes.Equal(input, []byte{0x15}): // Ctrl-U
+		m.query = m.query[:0]
+		m.filter()
+
+		return actionNone
+	case bytes.Equal(input, []byte{0x17}): // Ctrl-W
+		query := strings.TrimRight(string(m.query), " ")
+		m.query = []rune(query[:strings.LastIndex(query, " ")+1])
+		m.filter()
+
+		return actionNone
+	case input[0] == 0x1b:
+		// Unsupported escape sequence
+		return actionNone
+	}
+
+	changed := false
+	for len(input) > 0 {
+		r, size := utf8.DecodeRune(input)
+		input = input[size:]
+
+		if r == utf8.RuneError || r < ' ' || r == 0x7f {
+			continue
+		}
+
+		m.query = append(m.query, r)
+		changed = true
+	}
+
+	if changed {
+		m.filter()
+	}
+
+	return actionNone
+}
+
+// render returns the escape sequences that draw the picker over the whole
+// terminal: the prompt, the matching items and the preview of the selected
+// one.
+func (m *model) render(prompt string, preview string, width int, height int) string {
+	listHeight := max(1, (height-3)/2)
+	previewHeight := max(0, height-3-listHeight)
+
+	// Scroll the selected item into view
+	if m.selected < m.offset {
+		m.offset = m.selected
+	} else if m.selected >= m.offset+listHeight {
+		m.offset = m.selected - listHeight + 1
+	}
+
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(s)
+		b.WriteString("\x1b[K\r\n")
+	}
+
+	b.WriteString("\x1b[H")
+	line(truncate(prompt+string(m.query), width))
+	line(fmt.Sprintf("\x1b[2m  %d/%d\x1b[22m", len(m.matches), len(m.items)))
+
+	for row := 0; row < listHeight; row++ {
+		i := m.offset + row
+		if i >= len(m.matches) {
+			line("")
+			continue
+		}
+
+		line(m.renderMatch(m.matches[i], i == m.selected, width))
+	}
+
+	b.WriteString("\x1b[2m" + strings.Repeat("─", max(0, width)) + "\x1b[22m\x1b[K")
+
+	previewLines := strings.Split(strings.TrimRight(preview, "\n"), "\n")
+	for row := 0; row < previewHeight && row < len(previewLines); row++ {
+		b.WriteString("\r\n" + truncate(previewLines[row], width) + "\x1b[K")
+	}
+
+	// Clear the rest of the screen and put the cursor after the query
+	b.WriteString("\x1b[J")
+	fmt.Fprintf(&b, "\x1b[1;%dH", utf8.RuneCountInString(prompt)+len(m.query)+1)
+
+	return b.String()
+}
+
+// renderMatch draws the item, highlighting the runes that matched the query.
+func (m *model) renderMatch(mt match, selected bool, width int) string {
+	item := m.items[mt.index]
+
+	var b strings.Builder
+	if selected {
+		b.WriteString("\x1b[1m> ")
+	} else {
+		b.WriteString("  ")
+	}
+
+	runes := []rune(truncate(item.Text, width-2))
+	for i, r := range runes {
+		if slices.Contains(mt.positions, i) {
+			b.WriteString("\x1b[32m" + string(r) + "\x1b[39m")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	if remaining := width - 2 - len(runes) - 2; item.Detail != "" && remaining > 0 {
+		b.WriteString("  \x1b[2m" + truncate(item.Detail, remaining) + "\x1b[22m")
+	}
+
+	if selected {
+		b.WriteString("\x1b[0m")
+	}
+
+	return b.String()
+}
+
+// truncate shortens the text to at most width runes.
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+
+	return string(runes[:max(0, width)])
+}
@@ -0,0 +1,56 @@
+package picker
+
+import (
+	"testing"
+)
+
+func TestModelHandle(t *testing.T) {
+	m := newModel([]Item{{Text: "main"}, {Text: "feature/login"}, {Text: "feature/logout"}, {Text: "fix/lint"}})
+
+	type step struct {
+		input    string
+		action   action
+		selected string
+		matches  int
+	}
+
+	steps := []step{
+		{input: "\x1b[B", action: actionNone, selected: "feature/login", matches: 4},
+		{input: "lo", action: actionNone, selected: "feature/login", matches: 2},
+		{input: "gou", action: actionNone, selected: "feature/logout", matches: 1},
+		{input: "x", action: actionNone, matches: 0},
+		{input: "\r", action: actionNone, matches: 0},
+		{input: "\x7f\x7f\x7f\x7f", action: actionNone, selected: "feature/login", matches: 2},
+		{input: "\x15", action: actionNone, selected: "main", matches: 4},
+		{input: "\x1b[A", action: actionNone, selected: "main", matches: 4},
+		{input: "\x0e\x0e", action: actionNone, selected: "feature/logout", matches: 4},
+		{input: "\r", action: actionAccept, selected: "feature/logout", matches: 4},
+		{input: "\x1b", action: actionCancel, selected: "feature/logout", matches: 4},
+	}
+
+	for _, s := range steps {
+		// Keys arrive one at a time, escape sequences in a single read
+		var got action
+		for _, r := range []byte(s.input) {
+			if s.input[0] == 0x1b {
+				got = m.handle([]byte(s.input))
+				break
+			}
+
+			got = m.handle([]byte{r})
+		}
+
+		if got != s.action {
+			t.Errorf("handle(%q) = %v, want %v", s.input, got, s.action)
+		}
+
+		if len(m.matches) != s.matches {
+			t.Errorf("after %q: %d matches, want %d", s.input, len(m.matches), s.matches)
+		}
+
+		i, ok := m.selectedItem()
+		if selected := m.items[i].Text; ok && selected != s.selected {
+			t.Errorf("after %q: selected %q, want %q", s.input, selected, s.selected)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+# grove shell integration for bash
+#
+# Add the following to your ~/.bashrc:
+#   eval "$(grove shell-init bash)"
+
+grove() {
+    local cd_file ret
+    cd_file="$(mktemp)" || return
+    GROVE_CD_FILE="$cd_file" command grove "$@"
+    ret=$?
+    if [ $ret -eq 0 ] && [ -s "$cd_file" ]; then
+        cd -- "$(cat "$cd_file")" || ret=$?
+    fi
+    rm -f "$cd_file"
+    return $ret
+}
+
+source <(command grove completion bash)
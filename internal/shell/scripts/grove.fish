@@ -0,0 +1,17 @@
+# grove shell integration for fish
+#
+# Add the following to your ~/.config/fish/config.fish:
+#   grove shell-init fish | source
+
+function grove
+    set -l cd_file (mktemp); or return
+    GROVE_CD_FILE=$cd_file command grove $argv
+    set -l ret $status
+    if test $ret -eq 0; and test -s $cd_file
+        cd (cat $cd_file); or set ret $status
+    end
+    rm -f $cd_file
+    return $ret
+end
+
+command grove completion fish | source
@@ -0,0 +1,26 @@
+# grove shell integration for PowerShell
+#
+# Add the following to your $PROFILE:
+#   Invoke-Expression (& grove shell-init pwsh | Out-String)
+
+$__groveExecutable = (Get-Command grove -CommandType Application | Select-Object -First 1).Source
+
+function grove {
+    $cdFile = [System.IO.Path]::GetTempFileName()
+    $env:GROVE_CD_FILE = $cdFile
+    try {
+        & $__groveExecutable @args
+        $ret = $LASTEXITCODE
+    } finally {
+        Remove-Item Env:GROVE_CD_FILE -ErrorAction SilentlyContinue
+    }
+
+    if ($ret -eq 0 -and (Get-Item -LiteralPath $cdFile).Length -gt 0) {
+        Set-Location -LiteralPath (Get-Content -LiteralPath $cdFile -Raw)
+    }
+
+    Remove-Item -LiteralPath $cdFile -ErrorAction SilentlyContinue
+    $global:LASTEXITCODE = $ret
+}
+
+& $__groveExecutable completion powershell | Out-String | Invoke-Expression
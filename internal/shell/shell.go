@@ -0,0 +1,58 @@
+package shell
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CdFileEnv is the environment variable set by the shell integration to the
+// path of a file that grove writes the directory to change into.
+const CdFileEnv = "GROVE_CD_FILE"
+
+var (
+	ErrUnsupportedShell = errors.New("unsupported shell")
+
+	//go:embed scripts
+	scripts embed.FS
+
+	scriptNames = map[string]string{
+		"bash": "grove.bash",
+		"zsh":  "grove.zsh",
+		"fish": "grove.fish",
+		"pwsh": "grove.ps1",
+	}
+)
+
+// Shells returns the shells supported by the shell integration.
+func Shells() []string {
+	return []string{"bash", "zsh", "fish", "pwsh"}
+}
+
+// InitScript returns the shell integration script for the specified shell.
+func InitScript(shell string) (string, error) {
+	name, ok := scriptNames[shell]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnsupportedShell, shell)
+	}
+
+	script, err := scripts.ReadFile("scripts/" + name)
+	if err != nil {
+		return "", err
+	}
+
+	return string(script), nil
+}
+
+// ChangeDirectory requests the shell integration to change the shell's
+// working directory to path once grove exits. It is a no-op when grove is
+// not being run through the shell integration.
+func ChangeDirectory(path string) error {
+	cdFile := os.Getenv(CdFileEnv)
+	if cdFile == "" {
+		return nil
+	}
+
+	return os.WriteFile(cdFile, []byte(path), 0600)
+}
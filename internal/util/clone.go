@@ -0,0 +1,159 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+// CloneStats summarizes a cloned directory tree.
+type CloneStats struct {
+	// Files is the number of files cloned
+	Files int
+	// Bytes is the total size of the files cloned
+	Bytes int64
+	// Saved is the size of the files that share their storage with the
+	// source, i.e. that were reflinked or hard linked rather than copied
+	Saved int64
+}
+
+func (s *CloneStats) Add(other CloneStats) {
+	s.Files += other.Files
+	s.Bytes += other.Bytes
+	s.Saved += other.Saved
+}
+
+// Cloner clones directory trees using copy-on-write reflinks when the file
+// system supports them, and fallback when it doesn't.
+type Cloner struct {
+	Fallback config.CloneFallback
+	// noReflink is set once reflinking failed, to stop trying
+	noReflink bool
+}
+
+// CloneTree clones the directory tree at src to dest. Files that already
+// exist at dest are left alone.
+func (c *Cloner) CloneTree(src string, dest string) (CloneStats, error) {
+	var stats CloneStats
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			err = os.Symlink(link, target)
+			if err != nil && !os.IsExist(err) {
+				return err
+			}
+
+			return nil
+		case !d.Type().IsRegular():
+			return nil
+		}
+
+		if _, err := os.Lstat(target); err == nil {
+			return nil
+		}
+
+		shared, err := c.cloneFile(path, target, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+
+		stats.Files++
+		stats.Bytes += info.Size()
+		if shared {
+			stats.Saved += info.Size()
+		}
+
+		return nil
+	})
+
+	return stats, err
+}
+
+// cloneFile clones the file at src to dest, returning whether dest shares
+// its storage with src.
+func (c *Cloner) cloneFile(src string, dest string, perm os.FileMode) (bool, error) {
+	if !c.noReflink {
+		err := reflink(src, dest, perm)
+		if err == nil {
+			return true, nil
+		}
+
+		// Most likely the file system doesn't support reflinks, which won't
+		// change for the remaining files
+		c.noReflink = true
+	}
+
+	if c.Fallback == config.CloneFallbackHardlink {
+		err := os.Link(src, dest)
+		if err == nil {
+			return true, nil
+		}
+	}
+
+	return false, copyFile(src, dest, perm)
+}
+
+func copyFile(src string, dest string, perm os.FileMode) error {
+	s, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	d, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(d, s)
+	if err != nil {
+		d.Close()
+		return err
+	}
+
+	return d.Close()
+}
+
+// FormatBytes formats the number of bytes with a binary unit, e.g. 1.5 GiB.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestCloneTree(t *testing.T) {
+	src := t.TempDir()
+	dest := filepath.Join(t.TempDir(), "node_modules")
+
+	files := map[string]string{
+		"react/index.js":        "react",
+		"react/package.json":    "{}",
+		"left-pad/index.js":     "pad",
+		"left-pad/package.json": "{}",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Symlink("../react/index.js", filepath.Join(src, "left-pad", "react.js")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Existing files are left alone
+	if err := os.MkdirAll(filepath.Join(dest, "react"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dest, "react", "index.js"), []byte("local"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cloner := &Cloner{Fallback: config.CloneFallbackHardlink}
+	stats, err := cloner.CloneTree(src, dest)
+	if err != nil {
+		t.Fatalf("CloneTree() error = %v", err)
+	}
+
+	if stats.Files != 3 {
+		t.Errorf("Files = %d, want 3", stats.Files)
+	}
+
+	// Reflinked and hard linked files both share their storage
+	if stats.Saved != stats.Bytes {
+		t.Errorf("Saved = %d, want %d", stats.Saved, stats.Bytes)
+	}
+
+	want := map[string]string{
+		"react/index.js":        "local",
+		"react/package.json":    "{}",
+		"left-pad/index.js":     "pad",
+		"left-pad/package.json": "{}",
+		"left-pad/react.js":     "local",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dest, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{n: 0, want: "0 B"},
+		{n: 1023, want: "1023 B"},
+		{n: 1536, want: "1.5 KiB"},
+		{n: 5 << 30, want: "5.0 GiB"},
+	}
+
+	for _, tc := range tests {
+		if got := FormatBytes(tc.n); got != tc.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tc.n, got, tc.want)
+		}
+	}
+}
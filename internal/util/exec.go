@@ -2,32 +2,124 @@ package util
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/jacobdrury/grove/internal/config"
 )
 
-func ExecShellCmd(ctx context.Context, shell string, cmd string) error {
-	var command *exec.Cmd
+// processGroupGracePeriod is how long a command's process group has to exit
+// after being asked to terminate before it's killed.
+var processGroupGracePeriod = 5 * time.Second
+
+// processGroupPollInterval is how often a terminated process group is
+// checked for having exited.
+const processGroupPollInterval = 50 * time.Millisecond
+
+// processGroupWaitDelay is how long the command's output is drained after
+// its process group was terminated, in case processes that left the group
+// still hold it open.
+const processGroupWaitDelay = 500 * time.Millisecond
+
+// ShellCmd is a command run with a shell.
+type ShellCmd struct {
+	// Shell is the path or name of the shell executable
+	Shell string
+	// Mode is how the shell is invoked, plain when empty
+	Mode config.ShellMode
+	// Command is the command passed to the shell
+	Command string
+	// Dir is the directory the command is run in, the current working
+	// directory when empty
+	Dir string
+	// Env is appended to the environment of the current process
+	Env []string
+	// Stdin is the command's input, detached when nil
+	Stdin io.Reader
+	// Stdout and Stderr receive the command's output, both default to
+	// os.Stderr so grove's own stdout stays clean for piping
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ExecShellCmd runs the command with its shell. Commands with detached input
+// run in their own process group, which is terminated as a whole when ctx is
+// canceled.
+func ExecShellCmd(ctx context.Context, cmd ShellCmd) error {
+	args, err := shellArgs(cmd.Shell, cmd.Mode)
+	if err != nil {
+		return err
+	}
+
+	command := exec.CommandContext(ctx, cmd.Shell, append(args, cmd.Command)...)
+	command.Dir = cmd.Dir
+	command.Env = append(os.Environ(), cmd.Env...)
+	command.Stdin = cmd.Stdin
+
+	// A command reading from the terminal must stay in the terminal's
+	// foreground process group, where it receives Ctrl-C directly.
+	if cmd.Stdin == nil {
+		newProcessGroup(command)
+	}
+
+	command.Stdout = cmd.Stdout
+	if command.Stdout == nil {
+		command.Stdout = os.Stderr
+	}
+
+	command.Stderr = cmd.Stderr
+	if command.Stderr == nil {
+		command.Stderr = os.Stderr
+	}
+
+	return command.Run()
+}
+
+// shellArgs returns the arguments that make the shell run a command in the
+// specified mode, preceding the command itself.
+func shellArgs(shell string, mode config.ShellMode) ([]string, error) {
+	if mode == "" {
+		mode = config.ShellModePlain
+	}
+
+	switch mode {
+	case config.ShellModePlain, config.ShellModeLogin, config.ShellModeInteractive:
+	default:
+		return nil, fmt.Errorf("invalid shell mode %q, must be one of %s, %s, %s", mode, config.ShellModePlain, config.ShellModeLogin, config.ShellModeInteractive)
+	}
 
 	// Normalize shell name for comparison
 	shellBase := strings.ToLower(filepath.Base(shell))
 
 	switch shellBase {
 	case "powershell", "pwsh":
-		// PowerShell: use -Command
-		command = exec.CommandContext(ctx, shell, "-Command", cmd)
+		// PowerShell: use -Command, profiles are loaded unless disabled
+		if mode == config.ShellModePlain {
+			return []string{"-NoProfile", "-NonInteractive", "-Command"}, nil
+		}
+
+		return []string{"-Command"}, nil
 	case "cmd", "cmd.exe":
-		// cmd.exe: use /C
-		command = exec.CommandContext(ctx, shell, "/C", cmd)
+		// cmd.exe: use /C, /D disables AutoRun commands
+		if mode == config.ShellModePlain {
+			return []string{"/D", "/C"}, nil
+		}
+
+		return []string{"/C"}, nil
 	default:
 		// Unix shells: use -c
-		command = exec.CommandContext(ctx, shell, "-i", "-c", cmd)
+		switch mode {
+		case config.ShellModeLogin:
+			return []string{"-l", "-c"}, nil
+		case config.ShellModeInteractive:
+			return []string{"-i", "-c"}, nil
+		default:
+			return []string{"-c"}, nil
+		}
 	}
-
-	command.Stdout = os.Stdout
-	command.Stderr = os.Stderr
-
-	return command.Run()
 }
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jacobdrury/grove/internal/config"
+)
+
+func TestShellArgs(t *testing.T) {
+	tests := []struct {
+		shell   string
+		mode    config.ShellMode
+		want    []string
+		wantErr bool
+	}{
+		{shell: "/bin/sh", mode: "", want: []string{"-c"}},
+		{shell: "/bin/bash", mode: config.ShellModePlain, want: []string{"-c"}},
+		{shell: "/bin/zsh", mode: config.ShellModeLogin, want: []string{"-l", "-c"}},
+		{shell: "bash", mode: config.ShellModeInteractive, want: []string{"-i", "-c"}},
+		{shell: "pwsh", mode: config.ShellModePlain, want: []string{"-NoProfile", "-NonInteractive", "-Command"}},
+		{shell: "PowerShell", mode: config.ShellModeLogin, want: []string{"-Command"}},
+		{shell: "cmd.exe", mode: config.ShellModePlain, want: []string{"/D", "/C"}},
+		{shell: "cmd", mode: config.ShellModeInteractive, want: []string{"/C"}},
+		{shell: "/bin/sh", mode: "fancy", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.shell+" "+string(tc.mode), func(t *testing.T) {
+			got, err := shellArgs(tc.shell, tc.mode)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("shellArgs(%q, %q) error = %v, wantErr %v", tc.shell, tc.mode, err, tc.wantErr)
+			}
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("shellArgs(%q, %q) = %v, want %v", tc.shell, tc.mode, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExecShellCmdStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+
+	tests := []struct {
+		name  string
+		stdin string
+		want  string
+	}{
+		// Detached input reads as empty rather than blocking on the terminal
+		{name: "detached", want: ""},
+		{name: "attached", stdin: "input", want: "input"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out strings.Builder
+			cmd := ShellCmd{Shell: "/bin/sh", Command: "cat", Stdout: &out}
+			if tc.stdin != "" {
+				cmd.Stdin = strings.NewReader(tc.stdin)
+			}
+
+			err := ExecShellCmd(context.Background(), cmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if out.String() != tc.want {
+				t.Errorf("output = %q, want %q", out.String(), tc.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package util
 import (
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 func InDirectory[T any](dir string, f func() (T, error)) (T, error) {
@@ -42,3 +44,42 @@ func InDirectoryNoResult(dir string, f func() error) error {
 
 	return err
 }
+
+// RemoveEmptyParents removes each empty directory between path and root,
+// starting with the parent of path. The root directory itself is never
+// removed, and nothing is removed if path is not located within root.
+func RemoveEmptyParents(path string, root string) error {
+	dir := filepath.Dir(path)
+	for {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				dir = filepath.Dir(dir)
+				continue
+			}
+
+			return err
+		}
+
+		if len(entries) > 0 {
+			return nil
+		}
+
+		slog.Debug("removing empty directory", slog.String("path", dir))
+		err = os.Remove(dir)
+		if err != nil {
+			return err
+		}
+
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether the slash-separated name matches the pattern.
+// Patterns are matched segment by segment as by path.Match, and a `**`
+// segment matches any number of segments, e.g. `.idea/**` matches every file
+// in the `.idea` directory.
+func MatchGlob(pattern string, name string) bool {
+	return matchSegments(splitPath(pattern), splitPath(name), false)
+}
+
+// MatchGlobPrefix reports whether paths in the slash-separated directory can
+// match the pattern, i.e. whether the directory needs to be searched for
+// matches.
+func MatchGlobPrefix(pattern string, dir string) bool {
+	return matchSegments(splitPath(pattern), splitPath(dir), true)
+}
+
+func matchSegments(pattern []string, name []string, prefix bool) bool {
+	if len(name) == 0 {
+		if prefix {
+			return len(pattern) > 0
+		}
+
+		for _, segment := range pattern {
+			if segment != "**" {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if len(pattern) == 0 {
+		return false
+	}
+
+	if pattern[0] == "**" {
+		return matchSegments(pattern[1:], name, prefix) || matchSegments(pattern, name[1:], prefix)
+	}
+
+	matched, err := path.Match(pattern[0], name[0])
+
+	return err == nil && matched && matchSegments(pattern[1:], name[1:], prefix)
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" || p == "." {
+		return nil
+	}
+
+	return strings.Split(p, "/")
+}
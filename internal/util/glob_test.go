@@ -0,0 +1,54 @@
+package util
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: ".env*", name: ".env", want: true},
+		{pattern: ".env*", name: ".env.local", want: true},
+		{pattern: ".env*", name: "web/.env", want: false},
+		{pattern: ".idea/**", name: ".idea/workspace.xml", want: true},
+		{pattern: ".idea/**", name: ".idea/codeStyles/Project.xml", want: true},
+		{pattern: ".idea/**", name: ".idea", want: true},
+		{pattern: "**/.env", name: "web/api/.env", want: true},
+		{pattern: "**/.env", name: ".env", want: true},
+		{pattern: "**/node_modules", name: "web/node_modules", want: true},
+		{pattern: "**/node_modules", name: "web/node_modules/react", want: false},
+		{pattern: ".vscode/settings.json", name: ".vscode/settings.json", want: true},
+		{pattern: ".vscode/settings.json", name: ".vscode/launch.json", want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern+" "+tc.name, func(t *testing.T) {
+			if got := MatchGlob(tc.pattern, tc.name); got != tc.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchGlobPrefix(t *testing.T) {
+	tests := []struct {
+		pattern string
+		dir     string
+		want    bool
+	}{
+		{pattern: ".idea/**", dir: ".idea", want: true},
+		{pattern: ".vscode/settings.json", dir: ".vscode", want: true},
+		{pattern: ".vscode/settings.json", dir: "src", want: false},
+		{pattern: ".env*", dir: "web", want: false},
+		{pattern: "**/.env", dir: "web/api", want: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern+" "+tc.dir, func(t *testing.T) {
+			if got := MatchGlobPrefix(tc.pattern, tc.dir); got != tc.want {
+				t.Errorf("MatchGlobPrefix(%q, %q) = %v, want %v", tc.pattern, tc.dir, got, tc.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,56 @@ package util
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"runtime"
 
 	"github.com/jacobdrury/grove/internal/config"
+	"github.com/lmittmann/tint"
+	"github.com/mattn/go-colorable"
 )
 
+// LogFormat is the format log records are written in.
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
+// NewLogger returns a logger that writes records at or above level to
+// stderr, which keeps stdout free for output that is piped or parsed.
+func NewLogger(level slog.Level, format LogFormat) (*slog.Logger, error) {
+	switch format {
+	case LogFormatText:
+		var writer io.Writer = os.Stderr
+		if runtime.GOOS == "windows" {
+			writer = colorable.NewColorableStderr()
+		}
+
+		return slog.New(tint.NewHandler(writer, &tint.Options{
+			Level:   level,
+			NoColor: !isTerminal(os.Stderr),
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				// Remove the time attribute to de-clutter the output
+				if a.Key == slog.TimeKey && len(groups) == 0 {
+					return slog.Attr{}
+				}
+
+				return a
+			},
+		})), nil
+	case LogFormatJSON:
+		return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+			Level: level,
+		})), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q, must be %s or %s", format, LogFormatText, LogFormatJSON)
+	}
+}
+
 func LogInfo(ctx context.Context, msg string, args ...any) {
 	if config.Pipe(ctx) {
 		// No-op
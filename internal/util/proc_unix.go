@@ -0,0 +1,60 @@
+//go:build !windows
+
+package util
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Detach configures the command to run in its own session so it's not
+// terminated along with grove or its terminal.
+func Detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// ProcessExists returns whether a process with the PID is alive.
+func ProcessExists(pid int) bool {
+	// Signal 0 only checks whether the process exists, EPERM means it does
+	// but belongs to another user
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// newProcessGroup configures the command to run in its own process group,
+// which is sent SIGTERM when the command's context is canceled so that the
+// processes it spawned are terminated along with it. Whatever is left of the
+// group after the grace period, e.g. processes ignoring SIGTERM, is killed.
+func newProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := -cmd.Process.Pid
+
+		err := syscall.Kill(pgid, syscall.SIGTERM)
+		if err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(processGroupGracePeriod)
+		for time.Now().Before(deadline) {
+			// Signal 0 only checks whether the group still exists
+			if syscall.Kill(pgid, 0) != nil {
+				return nil
+			}
+
+			time.Sleep(processGroupPollInterval)
+		}
+
+		err = syscall.Kill(pgid, syscall.SIGKILL)
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+
+		return err
+	}
+	// Cancel already waited out the grace period, so the output only needs
+	// to be drained
+	cmd.WaitDelay = processGroupWaitDelay
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package util
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExecShellCmdKillsProcessGroup(t *testing.T) {
+	defer func(d time.Duration) { processGroupGracePeriod = d }(processGroupGracePeriod)
+	processGroupGracePeriod = 100 * time.Millisecond
+
+	marker := filepath.Join(t.TempDir(), "marker")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Both the shell and the process it spawns ignore SIGTERM, so only
+	// killing the whole group stops the marker from being written
+	start := time.Now()
+	err := ExecShellCmd(ctx, ShellCmd{
+		Shell:   "/bin/sh",
+		Command: "trap '' TERM; (sleep 1; touch " + marker + ") & wait",
+	})
+	if err == nil {
+		t.Fatal("ExecShellCmd() succeeded, want it to be killed")
+	}
+
+	// The timeout, the grace period and draining the output
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond+processGroupGracePeriod+processGroupWaitDelay {
+		t.Errorf("ExecShellCmd() returned after %v", elapsed)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("process ignoring SIGTERM survived the grace period")
+	}
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// IsInteractive reports whether stdin and stderr are terminals, in which case
+// the user can be prompted.
+func IsInteractive() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stderr)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Prompt writes the question to stderr and returns the trimmed, lowercase
+// answer read from the reader.
+func Prompt(r *bufio.Reader, question string) (string, error) {
+	fmt.Fprint(os.Stderr, question)
+
+	answer, err := r.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+
+	return strings.ToLower(strings.TrimSpace(answer)), nil
+}
@@ -0,0 +1,20 @@
+//go:build darwin
+
+package util
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflink clones src to dest with clonefile(2), sharing the file's blocks
+// until either copy is modified. Supported by APFS.
+func reflink(src string, dest string, perm os.FileMode) error {
+	err := unix.Clonefile(src, dest, unix.CLONE_NOFOLLOW)
+	if err != nil {
+		return err
+	}
+
+	return os.Chmod(dest, perm)
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package util
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflink clones src to dest with the FICLONE ioctl, sharing the file's
+// blocks until either copy is modified. Supported by e.g. btrfs and xfs.
+func reflink(src string, dest string, perm os.FileMode) error {
+	s, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	d, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	err = unix.IoctlFileClone(int(d.Fd()), int(s.Fd()))
+	if err != nil {
+		d.Close()
+		os.Remove(dest)
+		return err
+	}
+
+	return d.Close()
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package util
+
+import (
+	"errors"
+	"os"
+)
+
+// reflink is not supported on this operating system.
+func reflink(src string, dest string, perm os.FileMode) error {
+	return errors.ErrUnsupported
+}
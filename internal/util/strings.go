@@ -0,0 +1,47 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Slugify lowercases s and replaces every run of characters that are not
+// letters or digits with a single dash, e.g. `feature/Add Login` becomes
+// `feature-add-login`.
+func Slugify(s string) string {
+	var sb strings.Builder
+
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			dash = false
+
+			continue
+		}
+
+		if !dash && sb.Len() > 0 {
+			sb.WriteRune('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// FormatAgo formats the time relative to now, e.g. 3d ago.
+func FormatAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
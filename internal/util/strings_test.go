@@ -0,0 +1,28 @@
+package util
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "main", want: "main"},
+		{in: "feature/Add Login", want: "feature-add-login"},
+		{in: "feature/FM-3311-login", want: "feature-fm-3311-login"},
+		{in: "user1//fix__the--bug", want: "user1-fix-the-bug"},
+		{in: "/feature/x/", want: "feature-x"},
+		{in: "fix/über-größe", want: "fix-über-größe"},
+		{in: "release/1.2.3", want: "release-1-2-3"},
+		{in: "--", want: ""},
+		{in: "", want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := Slugify(tc.in); got != tc.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
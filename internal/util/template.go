@@ -0,0 +1,23 @@
+package util
+
+import (
+	"strings"
+	"text/template"
+)
+
+// RenderTemplate renders the Go template text with the specified data.
+// Referencing a field that does not exist in data is an error.
+func RenderTemplate(name string, text string, data any) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, data)
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,38 @@
+package util
+
+import "testing"
+
+func TestRenderTemplate(t *testing.T) {
+	type data struct {
+		Branch string
+		Port   int
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		data    any
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", text: "npm install", data: data{}, want: "npm install"},
+		{name: "fields", text: "PORT={{.Port}} {{.Branch}}", data: data{Branch: "feature/x", Port: 3001}, want: "PORT=3001 feature/x"},
+		{name: "map", text: "{{.Branch}}", data: map[string]string{"Branch": "main"}, want: "main"},
+		{name: "missing field", text: "{{.Missing}}", data: data{}, wantErr: true},
+		{name: "missing key", text: "{{.Missing}}", data: map[string]string{}, wantErr: true},
+		{name: "invalid", text: "{{.Port", data: data{}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RenderTemplate(tc.name, tc.text, tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("RenderTemplate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if got != tc.want {
+				t.Errorf("RenderTemplate() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}